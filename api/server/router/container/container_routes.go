@@ -95,6 +95,7 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 			Follow:     httputils.BoolValue(r, "follow"),
 			Timestamps: httputils.BoolValue(r, "timestamps"),
 			Since:      r.Form.Get("since"),
+			Until:      r.Form.Get("until"),
 			Tail:       r.Form.Get("tail"),
 			ShowStdout: stdout,
 			ShowStderr: stderr,
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/docker/docker/api/errors"
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/backend"
@@ -175,6 +176,17 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		return err
 	}
 
+	format := r.Form.Get("format")
+	switch format {
+	case "", types.ImageSaveFormatDocker, types.ImageSaveFormatOCI:
+	default:
+		return errors.NewBadRequestError(fmt.Errorf("invalid format %q: must be %q or %q", format, types.ImageSaveFormatDocker, types.ImageSaveFormatOCI))
+	}
+	excludeLayersFrom := r.Form["excludeLayersFrom"]
+	if format == types.ImageSaveFormatOCI && len(excludeLayersFrom) > 0 {
+		return errors.NewBadRequestError(fmt.Errorf("layers cannot be excluded from an archive in the %q format", types.ImageSaveFormatOCI))
+	}
+
 	w.Header().Set("Content-Type", "application/x-tar")
 
 	output := ioutils.NewWriteFlusher(w)
@@ -186,7 +198,7 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	if err := s.backend.ExportImage(names, format, excludeLayersFrom, output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
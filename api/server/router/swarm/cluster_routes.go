@@ -209,6 +209,16 @@ func (sr *swarmRouter) removeService(ctx context.Context, w http.ResponseWriter,
 }
 
 func (sr *swarmRouter) getServiceLogs(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return sr.swarmLogs(ctx, w, r, vars["id"], sr.backend.ServiceLogs)
+}
+
+func (sr *swarmRouter) getTaskLogs(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return sr.swarmLogs(ctx, w, r, vars["id"], sr.backend.TaskLogs)
+}
+
+// swarmLogs streams the logs returned by getLogs for the service or task
+// with the given name or ID.
+func (sr *swarmRouter) swarmLogs(ctx context.Context, w http.ResponseWriter, r *http.Request, name string, getLogs func(context.Context, string, *backend.ContainerLogsConfig, chan struct{}) error) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
 	}
@@ -223,12 +233,12 @@ func (sr *swarmRouter) getServiceLogs(ctx context.Context, w http.ResponseWriter
 		return fmt.Errorf("Bad parameters: you must choose at least one stream")
 	}
 
-	serviceName := vars["id"]
 	logsConfig := &backend.ContainerLogsConfig{
 		ContainerLogsOptions: basictypes.ContainerLogsOptions{
 			Follow:     httputils.BoolValue(r, "follow"),
 			Timestamps: httputils.BoolValue(r, "timestamps"),
 			Since:      r.Form.Get("since"),
+			Until:      r.Form.Get("until"),
 			Tail:       r.Form.Get("tail"),
 			ShowStdout: stdout,
 			ShowStderr: stderr,
@@ -242,13 +252,13 @@ func (sr *swarmRouter) getServiceLogs(ctx context.Context, w http.ResponseWriter
 	}
 
 	chStarted := make(chan struct{})
-	if err := sr.backend.ServiceLogs(ctx, serviceName, logsConfig, chStarted); err != nil {
+	if err := getLogs(ctx, name, logsConfig, chStarted); err != nil {
 		select {
 		case <-chStarted:
 			// The client may be expecting all of the data we're sending to
 			// be multiplexed, so send it through OutStream, which will
 			// have been set up to handle that if needed.
-			fmt.Fprintf(logsConfig.OutStream, "Error grabbing service logs: %v\n", err)
+			fmt.Fprintf(logsConfig.OutStream, "Error grabbing logs: %v\n", err)
 		default:
 			return err
 		}
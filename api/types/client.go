@@ -75,6 +75,7 @@ type ContainerLogsOptions struct {
 	ShowStdout bool
 	ShowStderr bool
 	Since      string
+	Until      string
 	Timestamps bool
 	Follow     bool
 	Tail       string
@@ -175,6 +176,9 @@ type ImageBuildOptions struct {
 	// specified here do not need to have a valid parent chain to match cache.
 	CacheFrom   []string
 	SecurityOpt []string
+	// Target is the name of the build stage to stop at. If empty, all the
+	// stages of the Dockerfile are built.
+	Target string
 }
 
 // ImageBuildResponse holds information
@@ -216,6 +220,20 @@ type ImageLoadResponse struct {
 	JSON bool
 }
 
+// Values for Format in ImageSaveOptions
+const (
+	ImageSaveFormatDocker = "docker"
+	ImageSaveFormatOCI    = "oci"
+)
+
+// ImageSaveOptions holds parameters to save images.
+type ImageSaveOptions struct {
+	Format string // Format is the format of the archive, "docker" (default) or "oci"
+	// ExcludeLayersFrom lists images whose layers are left out of the
+	// archive, as the daemon that loads it already has them.
+	ExcludeLayersFrom []string
+}
+
 // ImagePullOptions holds information to pull images.
 type ImagePullOptions struct {
 	All           bool
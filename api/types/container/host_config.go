@@ -287,6 +287,7 @@ type HostConfig struct {
 	PortBindings    nat.PortMap   // Port mapping between the exposed port (container) and the host
 	RestartPolicy   RestartPolicy // Restart policy to be used for the container
 	AutoRemove      bool          // Automatically remove container when it exits
+	OnUnhealthy     string        `json:",omitempty"` // Action to take when the container becomes unhealthy ("none", "restart" or "stop")
 	VolumeDriver    string        // Name of the volume driver used to mount volumes
 	VolumesFrom     []string      // List of volumes to take from other container
 
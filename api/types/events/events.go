@@ -11,6 +11,12 @@ const (
 	NetworkEventType = "network"
 	// PluginEventType is the event type that plugins generate
 	PluginEventType = "plugin"
+	// ServiceEventType is the event type that swarm services generate
+	ServiceEventType = "service"
+	// NodeEventType is the event type that swarm nodes generate
+	NodeEventType = "node"
+	// SecretEventType is the event type that swarm secrets generate
+	SecretEventType = "secret"
 	// VolumeEventType is the event type that volumes generate
 	VolumeEventType = "volume"
 )
@@ -0,0 +1,82 @@
+package logdriver
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestLogEntryMarshal(t *testing.T) {
+	e := &LogEntry{Source: "stdout", TimeNano: 150, Line: []byte("hello"), Partial: true}
+	b, err := e.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encoded as described in entry.proto
+	expected := []byte{
+		0x0a, 6, 's', 't', 'd', 'o', 'u', 't',
+		0x10, 0x96, 0x01,
+		0x1a, 5, 'h', 'e', 'l', 'l', 'o',
+		0x20, 1,
+	}
+	if !bytes.Equal(b, expected) {
+		t.Fatalf("expected %v, got %v", expected, b)
+	}
+	if e.Size() != len(expected) {
+		t.Fatalf("expected size %d, got %d", len(expected), e.Size())
+	}
+
+	var d LogEntry
+	if err := d.Unmarshal(b); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(e, &d) {
+		t.Fatalf("expected %+v, got %+v", e, d)
+	}
+}
+
+func TestLogEntryUnmarshalSkipsUnknownFields(t *testing.T) {
+	b := []byte{
+		0x0a, 6, 's', 't', 'd', 'e', 'r', 'r',
+		0x2a, 2, 'x', 'y', // field 5, bytes
+		0x31, 1, 2, 3, 4, 5, 6, 7, 8, // field 6, fixed64
+		0x1a, 2, 'h', 'i',
+	}
+	var e LogEntry
+	if err := e.Unmarshal(b); err != nil {
+		t.Fatal(err)
+	}
+	if e.Source != "stderr" || string(e.Line) != "hi" {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+
+	if err := e.Unmarshal([]byte{0x1a, 10, 'h'}); err == nil {
+		t.Fatal("expected an error for a truncated entry")
+	}
+}
+
+func TestLogEntryEncoderDecoder(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	enc := NewLogEntryEncoder(buf)
+	entries := []*LogEntry{
+		{Source: "stdout", TimeNano: 1, Line: []byte("first")},
+		{Source: "stderr", TimeNano: -1, Line: bytes.Repeat([]byte("a"), 2048)},
+		{Source: "stdout", TimeNano: 3, Partial: true},
+	}
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := NewLogEntryDecoder(buf)
+	for _, expected := range entries {
+		var e LogEntry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, &e) {
+			t.Fatalf("expected %+v, got %+v", expected, e)
+		}
+	}
+}
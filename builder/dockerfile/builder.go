@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/Sirupsen/logrus"
@@ -15,6 +16,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/builder"
+	"github.com/docker/docker/builder/dockerfile/command"
 	"github.com/docker/docker/builder/dockerfile/parser"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/pkg/stringid"
@@ -72,14 +74,17 @@ type Builder struct {
 	cmdSet           bool
 	disableCommit    bool
 	cacheBusted      bool
-	allowedBuildArgs map[string]bool // list of build-time args that are allowed for expansion/substitution and passing to commands in 'run'.
+	allowedBuildArgs map[string]*string  // list of build-time args that are allowed for expansion/substitution and passing to commands in 'run', with their default values.
+	allBuildArgs     map[string]struct{} // list of all build-time args found during parsing of the Dockerfile
+	metaArgs         map[string]*string  // build-time args declared before the first FROM, with their default values.
 	directive        parser.Directive
 
 	// TODO: remove once docker.Commit can receive a tag
 	id string
 
-	imageCache builder.ImageCache
-	from       builder.Image
+	imageCache    builder.ImageCache
+	from          builder.Image
+	imageContexts *imageContexts // helper for storing contexts from builds
 }
 
 // BuildManager implements builder.Backend and is shared across all Builder objects.
@@ -139,15 +144,16 @@ func NewBuilder(clientCtx context.Context, config *types.ImageBuildOptions, back
 		runConfig:        new(container.Config),
 		tmpContainers:    map[string]struct{}{},
 		id:               stringid.GenerateNonCryptoID(),
-		allowedBuildArgs: make(map[string]bool),
+		allowedBuildArgs: make(map[string]*string),
+		allBuildArgs:     make(map[string]struct{}),
+		metaArgs:         make(map[string]*string),
 		directive: parser.Directive{
 			EscapeSeen:           false,
 			LookingForDirectives: true,
 		},
 	}
-	if icb, ok := backend.(builder.ImageCacheBuilder); ok {
-		b.imageCache = icb.MakeImageCache(config.CacheFrom)
-	}
+	b.imageContexts = &imageContexts{b: b}
+	b.resetImageCache()
 
 	parser.SetEscapeToken(parser.DefaultEscapeToken, &b.directive) // Assume the default token for escape
 
@@ -161,6 +167,27 @@ func NewBuilder(clientCtx context.Context, config *types.ImageBuildOptions, back
 	return b, nil
 }
 
+// resetImageCache recreates the image cache so that cache lookups for a new
+// build stage don't depend on the results of the previous one.
+func (b *Builder) resetImageCache() {
+	if icb, ok := b.docker.(builder.ImageCacheBuilder); ok {
+		b.imageCache = icb.MakeImageCache(b.options.CacheFrom)
+	}
+	b.cacheBusted = false
+}
+
+// resetStage clears the state carried over from a previous build stage when
+// a FROM instruction starts a new one.
+func (b *Builder) resetStage() {
+	b.resetImageCache()
+	b.runConfig = new(container.Config)
+	b.image = ""
+	b.noBaseImage = false
+	b.maintainer = ""
+	b.cmdSet = false
+	b.allowedBuildArgs = make(map[string]*string)
+}
+
 // sanitizeRepoAndTags parses the raw "t" parameter received from the client
 // to a slice of repoAndTag.
 // It also validates each repoName and tag.
@@ -213,6 +240,7 @@ func sanitizeRepoAndTags(names []string) ([]reference.Named, error) {
 // * walk the AST and execute it by dispatching to handlers. If Remove
 //   or ForceRemove is set, additional cleanup around containers happens after
 //   processing.
+// * if a target stage is set, skip the stages that it does not depend on.
 // * Tag image, if applicable.
 // * Print a happy message and return the image ID.
 //
@@ -220,6 +248,7 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 	b.Stdout = stdout
 	b.Stderr = stderr
 	b.Output = out
+	defer b.imageContexts.unmount()
 
 	// If Dockerfile was not parsed yet, extract it from the Context
 	if b.dockerfile == nil {
@@ -233,6 +262,27 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		return "", err
 	}
 
+	for _, n := range b.dockerfile.Children {
+		if err := b.checkDispatch(n, false); err != nil {
+			return "", err
+		}
+	}
+
+	preamble, stages := splitStages(b.dockerfile.Children)
+	if b.options.Target != "" {
+		target := -1
+		for i, s := range stages {
+			if s.name == strings.ToLower(b.options.Target) {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			return "", perrors.Errorf("failed to reach build target %s in Dockerfile", b.options.Target)
+		}
+		markSkippedStages(stages, target)
+	}
+
 	if len(b.options.Labels) > 0 {
 		line := "LABEL "
 		for k, v := range b.options.Labels {
@@ -242,38 +292,68 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		if err != nil {
 			return "", err
 		}
-		b.dockerfile.Children = append(b.dockerfile.Children, node)
+		// labels are applied to the image that is the result of the build
+		if last := lastBuiltStage(stages); last != nil {
+			last.nodes = append(last.nodes, node)
+		} else {
+			preamble = append(preamble, node)
+		}
 	}
 
-	var shortImgID string
-	total := len(b.dockerfile.Children)
-	for _, n := range b.dockerfile.Children {
-		if err := b.checkDispatch(n, false); err != nil {
-			return "", err
+	total := len(preamble)
+	for _, s := range stages {
+		if !s.skip {
+			total += len(s.nodes)
 		}
 	}
 
-	for i, n := range b.dockerfile.Children {
-		select {
-		case <-b.clientCtx.Done():
-			logrus.Debug("Builder: build cancelled!")
-			fmt.Fprintf(b.Stdout, "Build cancelled")
-			return "", fmt.Errorf("Build cancelled")
-		default:
-			// Not cancelled yet, keep going...
-		}
+	var (
+		shortImgID string
+		step       int
+	)
+	dispatchNodes := func(nodes []*parser.Node) error {
+		for _, n := range nodes {
+			select {
+			case <-b.clientCtx.Done():
+				logrus.Debug("Builder: build cancelled!")
+				fmt.Fprintf(b.Stdout, "Build cancelled")
+				return fmt.Errorf("Build cancelled")
+			default:
+				// Not cancelled yet, keep going...
+			}
 
-		if err := b.dispatch(i, total, n); err != nil {
-			if b.options.ForceRemove {
+			if err := b.dispatch(step, total, n); err != nil {
+				if b.options.ForceRemove {
+					b.clearTmp()
+				}
+				return err
+			}
+			step++
+
+			b.imageContexts.update(b.image)
+			shortImgID = stringid.TruncateID(b.image)
+			fmt.Fprintf(b.Stdout, " ---> %s\n", shortImgID)
+			if b.options.Remove {
 				b.clearTmp()
 			}
-			return "", err
 		}
+		return nil
+	}
 
-		shortImgID = stringid.TruncateID(b.image)
-		fmt.Fprintf(b.Stdout, " ---> %s\n", shortImgID)
-		if b.options.Remove {
-			b.clearTmp()
+	if err := dispatchNodes(preamble); err != nil {
+		return "", err
+	}
+	for _, s := range stages {
+		if s.skip {
+			// Register the stage anyway so that the indexes of the following
+			// stages match their position in the Dockerfile.
+			if _, err := b.imageContexts.new(s.name); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if err := dispatchNodes(s.nodes); err != nil {
+			return "", err
 		}
 	}
 
@@ -281,7 +361,7 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 	// consumed during build. Return a warning, if there are any.
 	leftoverArgs := []string{}
 	for arg := range b.options.BuildArgs {
-		if !b.isBuildArgAllowed(arg) {
+		if _, ok := b.allBuildArgs[arg]; !ok && !BuiltinAllowedBuildArgs[arg] {
 			leftoverArgs = append(leftoverArgs, arg)
 		}
 	}
@@ -316,6 +396,115 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 	return b.image, nil
 }
 
+// buildStage holds the instructions of a build stage of the Dockerfile, as
+// found before any of them is dispatched.
+type buildStage struct {
+	name  string
+	nodes []*parser.Node
+	deps  []int // indexes of the previous stages this stage copies files from
+	skip  bool
+}
+
+// splitStages splits the instructions of the Dockerfile into build stages,
+// each of them starting with a FROM instruction. Instructions found before
+// the first FROM are returned separately.
+func splitStages(nodes []*parser.Node) ([]*parser.Node, []*buildStage) {
+	var (
+		preamble []*parser.Node
+		stages   []*buildStage
+		byName   = make(map[string]int)
+	)
+	for _, n := range nodes {
+		if n.Value == command.From {
+			stage := &buildStage{name: stageName(n)}
+			if _, exists := byName[stage.name]; !exists && stage.name != "" {
+				byName[stage.name] = len(stages)
+			}
+			stages = append(stages, stage)
+		}
+		if len(stages) == 0 {
+			preamble = append(preamble, n)
+			continue
+		}
+
+		current := len(stages) - 1
+		stage := stages[current]
+		stage.nodes = append(stage.nodes, n)
+
+		from, ok := copyFromFlag(n)
+		if !ok {
+			continue
+		}
+		dep, err := strconv.Atoi(from)
+		if err != nil {
+			var found bool
+			if dep, found = byName[strings.ToLower(from)]; !found {
+				// not a build stage, but an image
+				continue
+			}
+		}
+		if dep >= 0 && dep < current {
+			stage.deps = append(stage.deps, dep)
+		}
+	}
+	return preamble, stages
+}
+
+// markSkippedStages marks the stages that don't need to be built to produce
+// the target stage: the ones after it, and the ones it does not copy files
+// from, either directly or through another stage.
+func markSkippedStages(stages []*buildStage, target int) {
+	required := map[int]bool{target: true}
+	for i := target; i >= 0; i-- {
+		if !required[i] {
+			stages[i].skip = true
+			continue
+		}
+		for _, dep := range stages[i].deps {
+			required[dep] = true
+		}
+	}
+	for i := target + 1; i < len(stages); i++ {
+		stages[i].skip = true
+	}
+}
+
+// lastBuiltStage returns the last stage that is not skipped, or nil if there
+// is none.
+func lastBuiltStage(stages []*buildStage) *buildStage {
+	for i := len(stages) - 1; i >= 0; i-- {
+		if !stages[i].skip {
+			return stages[i]
+		}
+	}
+	return nil
+}
+
+// stageName returns the name given to a build stage with `FROM image AS name`.
+func stageName(n *parser.Node) string {
+	var args []string
+	for next := n.Next; next != nil; next = next.Next {
+		args = append(args, next.Value)
+	}
+	if len(args) == 3 && strings.EqualFold(args[1], "as") {
+		return strings.ToLower(args[2])
+	}
+	return ""
+}
+
+// copyFromFlag returns the value of the --from flag of a COPY instruction.
+func copyFromFlag(n *parser.Node) (string, bool) {
+	if n.Value != command.Copy {
+		return "", false
+	}
+	for _, flag := range n.Flags {
+		if strings.HasPrefix(flag, "--from=") {
+			return strings.TrimPrefix(flag, "--from="), true
+		}
+	}
+	return "", false
+}
+
 // Cancel cancels an ongoing Dockerfile build.
 func (b *Builder) Cancel() {
 	b.cancel()
@@ -0,0 +1,116 @@
+package dockerfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/builder/dockerfile/parser"
+)
+
+func TestSplitStagesWithTarget(t *testing.T) {
+	dockerfile := `ARG VERSION=1
+FROM busybox AS base
+RUN echo base
+FROM busybox AS unrelated
+RUN echo unrelated
+FROM busybox
+COPY --from=base /foo /foo
+FROM busybox AS target
+COPY --from=2 /foo /foo
+COPY --from=alpine /bar /bar
+FROM busybox
+COPY --from=target /foo /foo
+`
+	d := parser.Directive{}
+	parser.SetEscapeToken(parser.DefaultEscapeToken, &d)
+	ast, err := parser.Parse(strings.NewReader(dockerfile), &d)
+	if err != nil {
+		t.Fatalf("Error when parsing Dockerfile: %s", err)
+	}
+
+	preamble, stages := splitStages(ast.Children)
+	if len(preamble) != 1 {
+		t.Fatalf("Expected 1 instruction before the first FROM, got %d", len(preamble))
+	}
+	if len(stages) != 5 {
+		t.Fatalf("Expected 5 stages, got %d", len(stages))
+	}
+
+	expectedNames := []string{"base", "unrelated", "", "target", ""}
+	expectedDeps := [][]int{nil, nil, {0}, {2}, {3}}
+	for i, s := range stages {
+		if s.name != expectedNames[i] {
+			t.Fatalf("Expected stage %d to be named %q, got %q", i, expectedNames[i], s.name)
+		}
+		if !reflect.DeepEqual(s.deps, expectedDeps[i]) {
+			t.Fatalf("Expected stage %d to depend on %v, got %v", i, expectedDeps[i], s.deps)
+		}
+	}
+
+	markSkippedStages(stages, 3)
+	expectedSkip := []bool{false, true, false, false, true}
+	for i, s := range stages {
+		if s.skip != expectedSkip[i] {
+			t.Fatalf("Expected skip of stage %d to be %v, got %v", i, expectedSkip[i], s.skip)
+		}
+	}
+
+	if last := lastBuiltStage(stages); last != stages[3] {
+		t.Fatalf("Expected the target stage to be the last built stage")
+	}
+}
+
+func TestSplitStagesWithTargetAndMetaArgs(t *testing.T) {
+	dockerfile := `ARG BASE=busybox
+ARG NAME=first
+FROM ${BASE} AS first
+RUN echo first
+FROM ${BASE} AS second
+COPY --from=first /foo /foo
+`
+	d := parser.Directive{}
+	parser.SetEscapeToken(parser.DefaultEscapeToken, &d)
+	ast, err := parser.Parse(strings.NewReader(dockerfile), &d)
+	if err != nil {
+		t.Fatalf("Error when parsing Dockerfile: %s", err)
+	}
+
+	b := &Builder{}
+	for _, n := range ast.Children {
+		if err := b.checkDispatch(n, false); err != nil {
+			t.Fatalf("Error when checking %s: %s", n.Original, err)
+		}
+	}
+
+	preamble, stages := splitStages(ast.Children)
+	if len(preamble) != 2 {
+		t.Fatalf("Expected 2 instructions before the first FROM, got %d", len(preamble))
+	}
+	if len(stages) != 2 || stages[0].name != "first" || stages[1].name != "second" {
+		t.Fatalf("Expected stages first and second, got %v", stages)
+	}
+	if !reflect.DeepEqual(stages[1].deps, []int{0}) {
+		t.Fatalf("Expected stage second to depend on first, got %v", stages[1].deps)
+	}
+
+	markSkippedStages(stages, 0)
+	if stages[0].skip || !stages[1].skip {
+		t.Fatalf("Expected only the target stage first to be built")
+	}
+}
+
+func TestStageNameWithArg(t *testing.T) {
+	d := parser.Directive{}
+	parser.SetEscapeToken(parser.DefaultEscapeToken, &d)
+	ast, err := parser.Parse(strings.NewReader("ARG NAME=first\nFROM busybox AS ${NAME}\n"), &d)
+	if err != nil {
+		t.Fatalf("Error when parsing Dockerfile: %s", err)
+	}
+
+	b := &Builder{}
+	err = b.checkDispatch(ast.Children[1], false)
+	if err == nil || !strings.Contains(err.Error(), "variables are not supported in the name of a build stage") {
+		t.Fatalf("Expected an error for a variable in the name of a build stage, got: %v", err)
+	}
+}
@@ -169,32 +169,52 @@ func add(b *Builder, args []string, attributes map[string]bool, original string)
 		return err
 	}
 
-	return b.runContextCommand(args, true, true, "ADD")
+	return b.runContextCommand(args, true, true, "ADD", nil)
 }
 
 // COPY foo /path
 //
-// Same as 'ADD' but without the tar and remote url handling.
+// Same as 'ADD' but without the tar and remote url handling. With --from the
+// files are copied from the rootfs of a previous build stage or an image
+// instead of the build context.
 //
 func dispatchCopy(b *Builder, args []string, attributes map[string]bool, original string) error {
 	if len(args) < 2 {
 		return errAtLeastTwoArguments("COPY")
 	}
 
+	flFrom := b.flags.AddString("from", "")
+
 	if err := b.flags.Parse(); err != nil {
 		return err
 	}
 
-	return b.runContextCommand(args, false, false, "COPY")
+	var im *imageMount
+	if flFrom.IsUsed() {
+		var err error
+		im, err = b.imageContexts.get(flFrom.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.runContextCommand(args, false, false, "COPY", im)
 }
 
-// FROM imagename
+// FROM imagename[:tag | @digest] [AS build-stage-name]
 //
-// This sets the image the dockerfile will build on top of.
+// This sets the image the dockerfile will build on top of. Every FROM starts
+// a new build stage; the image of the last stage is the result of the build.
 //
 func from(b *Builder, args []string, attributes map[string]bool, original string) error {
-	if len(args) != 1 {
-		return errExactlyOneArgument("FROM")
+	ctxName := ""
+	if len(args) == 3 && strings.EqualFold(args[1], "as") {
+		ctxName = strings.ToLower(args[2])
+		if !validStageName.MatchString(ctxName) {
+			return fmt.Errorf("invalid name for build stage: %q, name can't start with a number or contain symbols", ctxName)
+		}
+	} else if len(args) != 1 {
+		return fmt.Errorf("FROM requires either one or three arguments")
 	}
 
 	if err := b.flags.Parse(); err != nil {
@@ -203,10 +223,12 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 
 	name := args[0]
 
-	var (
-		image builder.Image
-		err   error
-	)
+	var image builder.Image
+
+	b.resetStage()
+	if _, err := b.imageContexts.new(ctxName); err != nil {
+		return err
+	}
 
 	// Windows cannot support a container with no base image.
 	if name == api.NoBaseImageSpecifier {
@@ -216,16 +238,10 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 		b.image = ""
 		b.noBaseImage = true
 	} else {
-		// TODO: don't use `name`, instead resolve it to a digest
-		if !b.options.PullParent {
-			image, err = b.docker.GetImageOnBuild(name)
-			// TODO: shouldn't we error out if error is different from "not found" ?
-		}
-		if image == nil {
-			image, err = b.docker.PullOnBuild(b.clientCtx, name, b.options.AuthConfigs, b.Output)
-			if err != nil {
-				return err
-			}
+		var err error
+		image, err = b.pullOrGetImage(name)
+		if err != nil {
+			return err
 		}
 	}
 	b.from = image
@@ -233,6 +249,27 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 	return b.processImageFrom(image)
 }
 
+var validStageName = regexp.MustCompile(`^[a-z][a-z0-9-_\.]*$`)
+
+// pullOrGetImage returns the image referenced by name, pulling it if it is
+// not available locally or if the build was requested with --pull.
+func (b *Builder) pullOrGetImage(name string) (builder.Image, error) {
+	var image builder.Image
+	// TODO: don't use `name`, instead resolve it to a digest
+	if !b.options.PullParent {
+		image, _ = b.docker.GetImageOnBuild(name)
+		// TODO: shouldn't we error out if error is different from "not found" ?
+	}
+	if image == nil {
+		var err error
+		image, err = b.docker.PullOnBuild(b.clientCtx, name, b.options.AuthConfigs, b.Output)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return image, nil
+}
+
 // ONBUILD RUN echo yo
 //
 // ONBUILD triggers run when the image is used in a FROM statement.
@@ -375,17 +412,9 @@ func run(b *Builder, args []string, attributes map[string]bool, original string)
 	// lookup for same image built with same build time environment.
 	cmdBuildEnv := []string{}
 	configEnv := runconfigopts.ConvertKVStringsToMap(b.runConfig.Env)
-	for key, val := range b.options.BuildArgs {
-		if !b.isBuildArgAllowed(key) {
-			// skip build-args that are not in allowed list, meaning they have
-			// not been defined by an "ARG" Dockerfile command yet.
-			// This is an error condition but only if there is no "ARG" in the entire
-			// Dockerfile, so we'll generate any necessary errors after we parsed
-			// the entire file (see 'leftoverArgs' processing in evaluator.go )
-			continue
-		}
-		if _, ok := configEnv[key]; !ok && val != nil {
-			cmdBuildEnv = append(cmdBuildEnv, fmt.Sprintf("%s=%s", key, *val))
+	for key, val := range b.buildArgs() {
+		if _, ok := configEnv[key]; !ok {
+			cmdBuildEnv = append(cmdBuildEnv, fmt.Sprintf("%s=%s", key, val))
 		}
 	}
 
@@ -517,6 +546,7 @@ func healthcheck(b *Builder, args []string, attributes map[string]bool, original
 
 		flInterval := b.flags.AddString("interval", "")
 		flTimeout := b.flags.AddString("timeout", "")
+		flStartPeriod := b.flags.AddString("start-period", "")
 		flRetries := b.flags.AddString("retries", "")
 
 		if err := b.flags.Parse(); err != nil {
@@ -535,8 +565,24 @@ func healthcheck(b *Builder, args []string, attributes map[string]bool, original
 			}
 
 			healthcheck.Test = strslice.StrSlice(append([]string{typ}, cmdSlice...))
+		case "HTTP", "TCP":
+			probeArgs := handleJSONArgs(args, attributes)
+			if !attributes["json"] {
+				probeArgs = strings.Fields(strings.Join(probeArgs, " "))
+			}
+			if len(probeArgs) == 0 {
+				return fmt.Errorf("Missing address after HEALTHCHECK %s", typ)
+			}
+			if typ == "HTTP" && len(probeArgs) > 2 {
+				return fmt.Errorf("HEALTHCHECK HTTP takes a URL and an optional range of status codes")
+			}
+			if typ == "TCP" && len(probeArgs) > 1 {
+				return fmt.Errorf("HEALTHCHECK TCP takes exactly one address")
+			}
+
+			healthcheck.Test = strslice.StrSlice(append([]string{typ}, probeArgs...))
 		default:
-			return fmt.Errorf("Unknown type %#v in HEALTHCHECK (try CMD)", typ)
+			return fmt.Errorf("Unknown type %#v in HEALTHCHECK (try CMD, HTTP or TCP)", typ)
 		}
 
 		interval, err := parseOptInterval(flInterval)
@@ -551,6 +597,12 @@ func healthcheck(b *Builder, args []string, attributes map[string]bool, original
 		}
 		healthcheck.Timeout = timeout
 
+		startPeriod, err := parseOptInterval(flStartPeriod)
+		if err != nil {
+			return err
+		}
+		healthcheck.StartPeriod = startPeriod
+
 		if flRetries.Value != "" {
 			retries, err := strconv.ParseInt(flRetries.Value, 10, 32)
 			if err != nil {
@@ -727,9 +779,8 @@ func arg(b *Builder, args []string, attributes map[string]bool, original string)
 	}
 
 	var (
-		name       string
-		newValue   string
-		hasDefault bool
+		name     string
+		newValue *string
 	)
 
 	arg := args[0]
@@ -745,25 +796,30 @@ func arg(b *Builder, args []string, attributes map[string]bool, original string)
 		}
 
 		name = parts[0]
-		newValue = parts[1]
-		hasDefault = true
+		newValue = &parts[1]
 	} else {
 		name = arg
-		hasDefault = false
 	}
-	// add the arg to allowed list of build-time args from this step on.
-	b.allowedBuildArgs[name] = true
+	b.allBuildArgs[name] = struct{}{}
 
-	// If there is a default value associated with this arg then add it to the
-	// b.buildArgs if one is not already passed to the builder. The args passed
-	// to builder override the default value of 'arg'. Note that a 'nil' for
-	// a value means that the user specified "--build-arg FOO" and "FOO" wasn't
-	// defined as an env var - and in that case we DO want to use the default
-	// value specified in the ARG cmd.
-	if baValue, ok := b.options.BuildArgs[name]; (!ok || baValue == nil) && hasDefault {
-		b.options.BuildArgs[name] = &newValue
+	// An ARG before the first FROM is only in scope for the FROM
+	// instructions. It does not create a layer as there is no image yet.
+	if !b.hasFromImage() {
+		b.metaArgs[name] = newValue
+		return nil
 	}
 
+	// Inside a build stage an ARG without a default value picks up the one
+	// of the ARG with the same name declared before the first FROM, if any.
+	if newValue == nil {
+		newValue = b.metaArgs[name]
+	}
+
+	// add the arg to allowed list of build-time args from this step on, along
+	// with its default value if there is one. The args passed to builder
+	// override the default value of 'arg' (see buildArgs).
+	b.allowedBuildArgs[name] = newValue
+
 	return b.commit("", b.runConfig.Cmd, fmt.Sprintf("ARG %s", arg))
 }
 
@@ -2,6 +2,7 @@ package dockerfile
 
 import (
 	"fmt"
+	"io/ioutil"
 	"runtime"
 	"strings"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/builder/dockerfile/parser"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -20,7 +22,6 @@ type commandWithFunction struct {
 func TestCommandsExactlyOneArgument(t *testing.T) {
 	commands := []commandWithFunction{
 		{"MAINTAINER", func(args []string) error { return maintainer(nil, args, nil, "") }},
-		{"FROM", func(args []string) error { return from(nil, args, nil, "") }},
 		{"WORKDIR", func(args []string) error { return workdir(nil, args, nil, "") }},
 		{"USER", func(args []string) error { return user(nil, args, nil, "") }},
 		{"STOPSIGNAL", func(args []string) error { return stopSignal(nil, args, nil, "") }}}
@@ -191,6 +192,7 @@ func TestLabel(t *testing.T) {
 
 func TestFrom(t *testing.T) {
 	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true}
+	b.imageContexts = &imageContexts{b: b}
 
 	err := from(b, []string{"scratch"}, nil, "")
 
@@ -219,6 +221,58 @@ func TestFrom(t *testing.T) {
 	}
 }
 
+func TestFromMultiStage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not support FROM scratch")
+	}
+
+	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true}
+	b.imageContexts = &imageContexts{b: b}
+
+	if err := from(b, []string{"scratch", "AS", "Build"}, nil, ""); err != nil {
+		t.Fatalf("Error when executing from: %s", err.Error())
+	}
+	b.imageContexts.update("sha256:abc")
+
+	if err := from(b, []string{"scratch", "as", "build"}, nil, ""); err == nil || !strings.Contains(err.Error(), "duplicate name build") {
+		t.Fatalf("Expected duplicate name error, got: %v", err)
+	}
+
+	if err := from(b, []string{"scratch"}, nil, ""); err != nil {
+		t.Fatalf("Error when executing from: %s", err.Error())
+	}
+
+	for _, ref := range []string{"0", "build", "BUILD"} {
+		im, err := b.imageContexts.get(ref)
+		if err != nil {
+			t.Fatalf("Error when getting stage %s: %s", ref, err.Error())
+		}
+		if im.id != "sha256:abc" {
+			t.Fatalf("Stage %s should refer to image sha256:abc, got %s", ref, im.id)
+		}
+	}
+
+	if _, err := b.imageContexts.get("1"); err == nil || !strings.Contains(err.Error(), "refers current build block") {
+		t.Fatalf("Expected error for reference to current stage, got: %v", err)
+	}
+
+	if _, err := b.imageContexts.get("-1"); err == nil {
+		t.Fatalf("Expected error for invalid stage index")
+	}
+
+	invalidNames := []string{"1stage", "stage$", "a b"}
+	for _, name := range invalidNames {
+		err := from(b, []string{"scratch", "AS", name}, nil, "")
+		if err == nil || !strings.Contains(err.Error(), "invalid name for build stage") {
+			t.Fatalf("Expected invalid name error for %q, got: %v", name, err)
+		}
+	}
+
+	if err := from(b, []string{"scratch", "build"}, nil, ""); err == nil || !strings.Contains(err.Error(), "FROM requires either one or three arguments") {
+		t.Fatalf("Expected argument count error, got: %v", err)
+	}
+}
+
 func TestOnbuildIllegalTriggers(t *testing.T) {
 	triggers := []struct{ command, expectedError string }{
 		{"ONBUILD", "Chaining ONBUILD via `ONBUILD ONBUILD` isn't allowed"},
@@ -355,6 +409,39 @@ func TestHealthcheckCmd(t *testing.T) {
 	}
 }
 
+func TestHealthcheckHTTP(t *testing.T) {
+	b := &Builder{flags: &BFlags{flags: make(map[string]*Flag)}, runConfig: &container.Config{}, disableCommit: true}
+
+	if err := healthcheck(b, []string{"HTTP", "http://localhost:8080/health 200-299"}, nil, ""); err != nil {
+		t.Fatalf("Error should be empty, got: %s", err.Error())
+	}
+
+	expectedTest := strslice.StrSlice{"HTTP", "http://localhost:8080/health", "200-299"}
+
+	if !compareStrSlice(expectedTest, b.runConfig.Healthcheck.Test) {
+		t.Fatalf("Command should be set to %s, got %s", expectedTest, b.runConfig.Healthcheck.Test)
+	}
+}
+
+func TestHealthcheckTCP(t *testing.T) {
+	b := &Builder{flags: &BFlags{flags: make(map[string]*Flag)}, runConfig: &container.Config{}, disableCommit: true}
+
+	if err := healthcheck(b, []string{"TCP", "5432"}, map[string]bool{"json": false}, ""); err != nil {
+		t.Fatalf("Error should be empty, got: %s", err.Error())
+	}
+
+	expectedTest := strslice.StrSlice{"TCP", "5432"}
+
+	if !compareStrSlice(expectedTest, b.runConfig.Healthcheck.Test) {
+		t.Fatalf("Command should be set to %s, got %s", expectedTest, b.runConfig.Healthcheck.Test)
+	}
+
+	b = &Builder{flags: &BFlags{flags: make(map[string]*Flag)}, runConfig: &container.Config{}, disableCommit: true}
+	if err := healthcheck(b, []string{"TCP", "5432 5433"}, nil, ""); err == nil {
+		t.Fatal("Expected an error for a TCP healthcheck with two addresses")
+	}
+}
+
 func TestEntrypoint(t *testing.T) {
 	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true}
 
@@ -462,7 +549,7 @@ func TestStopSignal(t *testing.T) {
 func TestArg(t *testing.T) {
 	buildOptions := &types.ImageBuildOptions{BuildArgs: make(map[string]*string)}
 
-	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true, allowedBuildArgs: make(map[string]bool), options: buildOptions}
+	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true, image: "sha256:1234", allowedBuildArgs: make(map[string]*string), allBuildArgs: make(map[string]struct{}), options: buildOptions}
 
 	argName := "foo"
 	argVal := "bar"
@@ -472,27 +559,60 @@ func TestArg(t *testing.T) {
 		t.Fatalf("Error should be empty, got: %s", err.Error())
 	}
 
-	allowed, ok := b.allowedBuildArgs[argName]
-
-	if !ok {
+	if _, ok := b.allowedBuildArgs[argName]; !ok {
 		t.Fatalf("%s argument should be allowed as a build arg", argName)
 	}
 
-	if !allowed {
-		t.Fatalf("%s argument was present in map but disallowed as a build arg", argName)
-	}
-
-	val, ok := b.options.BuildArgs[argName]
+	val, ok := b.buildArgs()[argName]
 
 	if !ok {
 		t.Fatalf("%s argument should be a build arg", argName)
 	}
 
-	if *val != "bar" {
+	if val != "bar" {
 		t.Fatalf("%s argument should have default value 'bar', got %s", argName, val)
 	}
 }
 
+func TestFromWithArg(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not support FROM scratch")
+	}
+
+	buildOptions := &types.ImageBuildOptions{BuildArgs: make(map[string]*string)}
+	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true, allowedBuildArgs: make(map[string]*string), allBuildArgs: make(map[string]struct{}), metaArgs: make(map[string]*string), options: buildOptions, Stdout: ioutil.Discard}
+	b.imageContexts = &imageContexts{b: b}
+
+	d := parser.Directive{}
+	parser.SetEscapeToken(parser.DefaultEscapeToken, &d)
+	ast, err := parser.Parse(strings.NewReader("ARG BASE=scratch\nARG NAME=first\nFROM ${BASE} AS first\nARG NAME\n"), &d)
+	if err != nil {
+		t.Fatalf("Error when parsing Dockerfile: %s", err)
+	}
+
+	for i, n := range ast.Children {
+		if err := b.dispatch(i, len(ast.Children), n); err != nil {
+			t.Fatalf("Error when dispatching %s: %s", n.Original, err)
+		}
+	}
+
+	if !b.noBaseImage {
+		t.Fatalf("FROM should have been expanded to scratch")
+	}
+
+	if _, err := b.imageContexts.get("first"); err == nil || !strings.Contains(err.Error(), "refers current build block") {
+		t.Fatalf("Build stage should have been named first, got: %v", err)
+	}
+
+	if val := b.buildArgs()["NAME"]; val != "first" {
+		t.Fatalf("ARG without default value should use the value declared before FROM, got %q", val)
+	}
+
+	if _, ok := b.buildArgs()["BASE"]; ok {
+		t.Fatalf("ARG declared before FROM should not be in scope of the build stage")
+	}
+}
+
 func TestShell(t *testing.T) {
 	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true}
 
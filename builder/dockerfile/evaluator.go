@@ -39,6 +39,7 @@ var replaceEnvAllowed = map[string]bool{
 	command.User:       true,
 	command.StopSignal: true,
 	command.Arg:        true,
+	command.From:       true,
 }
 
 // Certain commands are allowed to have their args split into more
@@ -149,22 +150,24 @@ func (b *Builder) dispatch(stepN int, stepTotal int, ast *parser.Node) error {
 	// a subsequent one. So, putting the buildArgs list after the Config.Env
 	// list, in 'envs', is safe.
 	envs := b.runConfig.Env
-	for key, val := range b.options.BuildArgs {
-		if !b.isBuildArgAllowed(key) {
-			// skip build-args that are not in allowed list, meaning they have
-			// not been defined by an "ARG" Dockerfile command yet.
-			// This is an error condition but only if there is no "ARG" in the entire
-			// Dockerfile, so we'll generate any necessary errors after we parsed
-			// the entire file (see 'leftoverArgs' processing in evaluator.go )
-			continue
-		}
-		envs = append(envs, fmt.Sprintf("%s=%s", key, *val))
+	buildArgs := b.buildArgs()
+	if cmd == command.From {
+		// FROM starts a new build stage, so neither the environment nor the
+		// args of the previous stage apply: only the args declared before
+		// the first FROM can be used.
+		envs = nil
+		buildArgs = b.metaArgsValues()
+	}
+	for key, val := range buildArgs {
+		envs = append(envs, fmt.Sprintf("%s=%s", key, val))
 	}
 	for ast.Next != nil {
 		ast = ast.Next
 		var str string
 		str = ast.Value
-		if replaceEnvAllowed[cmd] {
+		// Only the image of a FROM instruction is expanded, the name of the
+		// build stage has to be known before the stages are dispatched.
+		if replaceEnvAllowed[cmd] && (cmd != command.From || i == 0) {
 			var err error
 			var words []string
 
@@ -218,6 +221,14 @@ func (b *Builder) checkDispatch(ast *parser.Node, onbuild bool) error {
 		return err
 	}
 
+	// The build stages are resolved before any instruction is dispatched, so
+	// their names cannot depend on variables.
+	if cmd == command.From {
+		if name := stageName(ast); strings.Contains(name, "$") {
+			return fmt.Errorf("variables are not supported in the name of a build stage: %s", name)
+		}
+	}
+
 	// The instruction itself is ONBUILD, we will make sure it follows with at
 	// least one argument
 	if upperCasedCmd == "ONBUILD" {
@@ -0,0 +1,144 @@
+package dockerfile
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/builder"
+	"github.com/pkg/errors"
+)
+
+// imageContexts is a helper for stacking up built image rootfs and reusing
+// them as contexts
+type imageContexts struct {
+	b      *Builder
+	list   []*imageMount
+	byName map[string]*imageMount
+	byRef  map[string]*imageMount
+}
+
+// new starts a new build stage. An empty name means the stage can only be
+// referred to by its index.
+func (ic *imageContexts) new(name string) (*imageMount, error) {
+	im := &imageMount{ic: ic}
+	if len(name) > 0 {
+		if ic.byName == nil {
+			ic.byName = make(map[string]*imageMount)
+		}
+		if _, ok := ic.byName[name]; ok {
+			return nil, errors.Errorf("duplicate name %s", name)
+		}
+		ic.byName[name] = im
+	}
+	ic.list = append(ic.list, im)
+	return im, nil
+}
+
+// update records the latest image ID of the current build stage.
+func (ic *imageContexts) update(imageID string) {
+	if len(ic.list) == 0 {
+		return
+	}
+	ic.list[len(ic.list)-1].id = imageID
+}
+
+func (ic *imageContexts) validate(i int) error {
+	if i < 0 || i >= len(ic.list)-1 {
+		var extraMsg string
+		if i == len(ic.list)-1 {
+			extraMsg = " refers current build block"
+		}
+		return errors.Errorf("invalid from flag value %d%s", i, extraMsg)
+	}
+	return nil
+}
+
+// get returns the image mount for a previous build stage referred to by its
+// index or name. Any other value is treated as an image reference.
+func (ic *imageContexts) get(indexOrName string) (*imageMount, error) {
+	index, err := strconv.Atoi(indexOrName)
+	if err == nil {
+		if err := ic.validate(index); err != nil {
+			return nil, err
+		}
+		return ic.list[index], nil
+	}
+	if im, ok := ic.byName[strings.ToLower(indexOrName)]; ok {
+		if im == ic.list[len(ic.list)-1] {
+			return nil, errors.Errorf("invalid from flag value %s refers current build block", indexOrName)
+		}
+		return im, nil
+	}
+	if im, ok := ic.byRef[indexOrName]; ok {
+		return im, nil
+	}
+	img, err := ic.b.pullOrGetImage(indexOrName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid from flag value %s", indexOrName)
+	}
+	im := &imageMount{ic: ic, id: img.ImageID()}
+	if ic.byRef == nil {
+		ic.byRef = make(map[string]*imageMount)
+	}
+	ic.byRef[indexOrName] = im
+	return im, nil
+}
+
+// unmount releases all the image rootfs that were mounted during the build.
+func (ic *imageContexts) unmount() (retErr error) {
+	release := func(im *imageMount) {
+		if err := im.unmount(); err != nil {
+			logrus.Error(err)
+			retErr = err
+		}
+	}
+	for _, im := range ic.list {
+		release(im)
+	}
+	for _, im := range ic.byRef {
+		release(im)
+	}
+	return
+}
+
+// imageMount is a reference to an image that can be used as a builder.Context.
+// The image rootfs is only mounted the first time the context is requested.
+type imageMount struct {
+	ic      *imageContexts
+	id      string
+	ctx     builder.Context
+	release func() error
+}
+
+func (im *imageMount) context() (builder.Context, error) {
+	if im.ctx == nil {
+		if im.id == "" {
+			return nil, errors.Errorf("could not copy from empty context")
+		}
+		p, release, err := im.ic.b.docker.MountImage(im.id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to mount %s", im.id)
+		}
+		ctx, err := builder.NewLazyContext(p)
+		if err != nil {
+			release()
+			return nil, errors.Wrapf(err, "failed to create lazycontext for %s", p)
+		}
+		im.release = release
+		im.ctx = ctx
+	}
+	return im.ctx, nil
+}
+
+func (im *imageMount) unmount() error {
+	if im.release == nil {
+		return nil
+	}
+	if err := im.release(); err != nil {
+		return errors.Wrapf(err, "failed to unmount previous build image %s", im.id)
+	}
+	im.release = nil
+	im.ctx = nil
+	return nil
+}
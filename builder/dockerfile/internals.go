@@ -91,8 +91,16 @@ type copyInfo struct {
 	decompress bool
 }
 
-func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string) error {
-	if b.context == nil {
+func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string, imageSource *imageMount) error {
+	srcContext := b.context
+	if imageSource != nil {
+		var err error
+		srcContext, err = imageSource.context()
+		if err != nil {
+			return err
+		}
+	}
+	if srcContext == nil {
 		return fmt.Errorf("No context given. Impossible to use %s", cmdName)
 	}
 
@@ -128,7 +136,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 			continue
 		}
 		// not a URL
-		subInfos, err := b.calcCopyInfo(cmdName, orig, allowLocalDecompression, true)
+		subInfos, err := b.calcCopyInfo(srcContext, cmdName, orig, allowLocalDecompression, true)
 		if err != nil {
 			return err
 		}
@@ -294,7 +302,7 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 	return &builder.HashedFileInfo{FileInfo: builder.PathFileInfo{FileInfo: tmpFileSt, FilePath: tmpFileName}, FileHash: hash}, nil
 }
 
-func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
+func (b *Builder) calcCopyInfo(srcContext builder.Context, cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
 
 	// Work in daemon-specific OS filepath semantics
 	origPath = filepath.FromSlash(origPath)
@@ -307,7 +315,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	// Deal with wildcards
 	if allowWildcards && containsWildcards(origPath) {
 		var copyInfos []copyInfo
-		if err := b.context.Walk("", func(path string, info builder.FileInfo, err error) error {
+		if err := srcContext.Walk("", func(path string, info builder.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
@@ -321,7 +329,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 			// Note we set allowWildcards to false in case the name has
 			// a * in it
-			subInfos, err := b.calcCopyInfo(cmdName, path, allowLocalDecompression, false)
+			subInfos, err := b.calcCopyInfo(srcContext, cmdName, path, allowLocalDecompression, false)
 			if err != nil {
 				return err
 			}
@@ -335,7 +343,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 	// Must be a dir or a file
 
-	statPath, fi, err := b.context.Stat(origPath)
+	statPath, fi, err := srcContext.Stat(origPath)
 	if err != nil {
 		return nil, err
 	}
@@ -354,7 +362,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	}
 	// Must be a dir
 	var subfiles []string
-	err = b.context.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
+	err = srcContext.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -657,7 +665,7 @@ func (b *Builder) parseDockerfile() error {
 }
 
 // determine if build arg is part of built-in args or user
-// defined args in Dockerfile at any point in time.
+// defined args in the current build stage at any point in time.
 func (b *Builder) isBuildArgAllowed(arg string) bool {
 	if _, ok := BuiltinAllowedBuildArgs[arg]; ok {
 		return true
@@ -667,3 +675,53 @@ func (b *Builder) isBuildArgAllowed(arg string) bool {
 	}
 	return false
 }
+
+// metaArgsValues returns the build-time args declared before the first FROM
+// mapped to their values, with the values passed to the builder taking
+// precedence over the defaults. Only these args can be expanded in FROM.
+func (b *Builder) metaArgsValues() map[string]string {
+	m := make(map[string]string)
+	for key, def := range b.metaArgs {
+		if val, ok := b.options.BuildArgs[key]; ok && val != nil {
+			m[key] = *val
+		} else if def != nil {
+			m[key] = *def
+		}
+	}
+	return m
+}
+
+// hasFromImage returns true if a FROM instruction has been processed, that is
+// if the builder is inside a build stage.
+func (b *Builder) hasFromImage() bool {
+	return b.image != "" || b.noBaseImage
+}
+
+// buildArgs returns the build-time args that are in scope, mapped to their
+// values. Values passed to the builder take precedence over the defaults
+// declared by "ARG" instructions.
+func (b *Builder) buildArgs() map[string]string {
+	m := make(map[string]string)
+	for key, val := range b.options.BuildArgs {
+		if !b.isBuildArgAllowed(key) {
+			// skip build-args that are not in allowed list, meaning they have
+			// not been defined by an "ARG" Dockerfile command yet.
+			// This is an error condition but only if there is no "ARG" in the entire
+			// Dockerfile, so we'll generate any necessary errors after we parsed
+			// the entire file (see 'leftoverArgs' processing in builder.go )
+			continue
+		}
+		// Note that a 'nil' for a value means that the user specified
+		// "--build-arg FOO" and "FOO" wasn't defined as an env var - and in
+		// that case we DO want to use the default value specified in the ARG cmd.
+		if val != nil {
+			m[key] = *val
+		}
+	}
+	for key, val := range b.allowedBuildArgs {
+		if _, ok := m[key]; !ok && val != nil {
+			m[key] = *val
+		}
+	}
+	return m
+}
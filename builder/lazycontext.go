@@ -0,0 +1,164 @@
+package builder
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/docker/docker/pkg/pools"
+	"github.com/docker/docker/pkg/symlink"
+)
+
+// NewLazyContext creates a new LazyContext. LazyContext defines a hashed build
+// context based on a root directory. Individual files are hashed first time
+// they are asked. It is not safe to call methods of LazyContext concurrently.
+func NewLazyContext(root string) (Context, error) {
+	return &lazyContext{
+		root: root,
+		sums: make(map[string]string),
+	}, nil
+}
+
+type lazyContext struct {
+	root string
+	sums map[string]string
+}
+
+func (c *lazyContext) Close() error {
+	return nil
+}
+
+func (c *lazyContext) Open(path string) (io.ReadCloser, error) {
+	cleanPath, fullPath, err := c.normalize(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := os.Open(fullPath)
+	if err != nil {
+		return nil, convertPathError(err, cleanPath)
+	}
+	return r, nil
+}
+
+func (c *lazyContext) Stat(path string) (string, FileInfo, error) {
+	cleanPath, fullPath, err := c.normalize(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	st, err := os.Lstat(fullPath)
+	if err != nil {
+		return "", nil, convertPathError(err, cleanPath)
+	}
+
+	relPath, err := filepath.Rel(c.root, fullPath)
+	if err != nil {
+		return "", nil, convertPathError(err, cleanPath)
+	}
+
+	sum, ok := c.sums[relPath]
+	if !ok {
+		sum, err = c.prepareHash(relPath, st)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	fi := &HashedFileInfo{PathFileInfo{st, fullPath, filepath.Base(cleanPath)}, sum}
+	return relPath, fi, nil
+}
+
+func (c *lazyContext) Walk(root string, walkFn WalkFunc) error {
+	_, fullPath, err := c.normalize(root)
+	if err != nil {
+		return err
+	}
+	return filepath.Walk(fullPath, func(fullPath string, fi os.FileInfo, walkErr error) error {
+		// fi is nil when the path could not be read
+		if walkErr != nil {
+			return walkErr
+		}
+		relPath, err := filepath.Rel(c.root, fullPath)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		sum, ok := c.sums[relPath]
+		if !ok {
+			sum, err = c.prepareHash(relPath, fi)
+			if err != nil {
+				return err
+			}
+		}
+
+		hfi := &HashedFileInfo{PathFileInfo{FileInfo: fi, FilePath: fullPath}, sum}
+		return walkFn(relPath, hfi, nil)
+	})
+}
+
+// prepareHash calculates a checksum for the file at relPath from its tar
+// header and content, so that a COPY from an unchanged path produces the
+// same cache key between builds.
+func (c *lazyContext) prepareHash(relPath string, fi os.FileInfo) (string, error) {
+	p := filepath.Join(c.root, relPath)
+
+	var link string
+	if fi.Mode()&os.ModeSymlink != 0 {
+		var err error
+		link, err = os.Readlink(p)
+		if err != nil {
+			return "", err
+		}
+	}
+	hdr, err := tar.FileInfoHeader(fi, link)
+	if err != nil {
+		return "", err
+	}
+	hdr.Name = filepath.ToSlash(relPath)
+
+	h := sha256.New()
+	for _, kv := range [][2]string{
+		{"name", hdr.Name},
+		{"mode", strconv.FormatInt(hdr.Mode, 10)},
+		{"uid", strconv.Itoa(hdr.Uid)},
+		{"gid", strconv.Itoa(hdr.Gid)},
+		{"size", strconv.FormatInt(hdr.Size, 10)},
+		{"typeflag", string([]byte{hdr.Typeflag})},
+		{"linkname", hdr.Linkname},
+	} {
+		fmt.Fprintf(h, "%s%s", kv[0], kv[1])
+	}
+
+	if fi.Mode().IsRegular() && fi.Size() > 0 {
+		f, err := os.Open(p)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		if _, err := pools.Copy(h, f); err != nil {
+			return "", err
+		}
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	c.sums[relPath] = sum
+	return sum, nil
+}
+
+func (c *lazyContext) normalize(path string) (cleanPath, fullPath string, err error) {
+	cleanPath = filepath.Clean(string(os.PathSeparator) + path)[1:]
+	fullPath, err = symlink.FollowSymlinkInScope(filepath.Join(c.root, path), c.root)
+	if err != nil {
+		return "", "", fmt.Errorf("Forbidden path outside the build context: %s (%s)", path, fullPath)
+	}
+	return
+}
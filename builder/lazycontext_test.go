@@ -0,0 +1,78 @@
+package builder
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLazyContextStatAndWalk(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-lazycontext-test")
+	defer cleanup()
+
+	subdir := createTestTempSubdir(t, contextDir, "dir")
+	createTestTempFile(t, contextDir, DefaultDockerfileName, dockerfileContents, 0777)
+	createTestTempFile(t, subdir, "file", testfileContents, 0777)
+
+	ctx, err := NewLazyContext(contextDir)
+	if err != nil {
+		t.Fatalf("Error when creating lazy context: %s", err)
+	}
+	defer ctx.Close()
+
+	rel, fi, err := ctx.Stat(DefaultDockerfileName)
+	if err != nil {
+		t.Fatalf("Error when calling Stat: %s", err)
+	}
+	if rel != DefaultDockerfileName {
+		t.Fatalf("Stat returned wrong relative path, expected %s, got %s", DefaultDockerfileName, rel)
+	}
+	sum := fi.(Hashed).Hash()
+	if sum == "" {
+		t.Fatalf("Stat returned an empty hash for %s", DefaultDockerfileName)
+	}
+
+	walked := map[string]string{}
+	if err := ctx.Walk("", func(path string, fi FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		walked[path] = fi.(Hashed).Hash()
+		return nil
+	}); err != nil {
+		t.Fatalf("Error when walking lazy context: %s", err)
+	}
+
+	if walked[DefaultDockerfileName] != sum {
+		t.Fatalf("Walk and Stat returned different hashes for %s: %s and %s", DefaultDockerfileName, walked[DefaultDockerfileName], sum)
+	}
+	filePath := filepath.Join(filepath.Base(subdir), "file")
+	if _, ok := walked[filePath]; !ok {
+		t.Fatalf("Walk did not visit %s", filePath)
+	}
+	if walked[filePath] == sum {
+		t.Fatalf("Files with different content should not have the same hash")
+	}
+
+	if _, _, err := ctx.Stat("../../etc/passwd"); err == nil {
+		t.Fatalf("Stat should fail for a path outside the context")
+	}
+}
+
+func TestLazyContextWalkError(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-lazycontext-test")
+	defer cleanup()
+
+	ctx, err := NewLazyContext(contextDir)
+	if err != nil {
+		t.Fatalf("Error when creating lazy context: %s", err)
+	}
+	defer ctx.Close()
+
+	err = ctx.Walk("nonexistent", func(path string, fi FileInfo, err error) error {
+		t.Fatalf("Walk should not visit %s", path)
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("Walk should fail for a path that does not exist")
+	}
+}
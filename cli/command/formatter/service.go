@@ -190,7 +190,8 @@ func (ctx *serviceInspectContext) UpdateStatusStarted() string {
 }
 
 func (ctx *serviceInspectContext) UpdateIsCompleted() bool {
-	return ctx.Service.UpdateStatus.State == swarm.UpdateStateCompleted && ctx.Service.UpdateStatus.CompletedAt != nil
+	state := ctx.Service.UpdateStatus.State
+	return (state == swarm.UpdateStateCompleted || state == swarm.UpdateStateRollbackCompleted) && ctx.Service.UpdateStatus.CompletedAt != nil
 }
 
 func (ctx *serviceInspectContext) UpdateStatusCompleted() string {
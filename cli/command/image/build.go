@@ -60,6 +60,7 @@ type buildOptions struct {
 	securityOpt    []string
 	networkMode    string
 	squash         bool
+	target         string
 }
 
 // NewBuildCommand creates a new `docker build` command
@@ -108,6 +109,7 @@ func NewBuildCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVar(&options.compress, "compress", false, "Compress the build context using gzip")
 	flags.StringSliceVar(&options.securityOpt, "security-opt", []string{}, "Security options")
 	flags.StringVar(&options.networkMode, "network", "default", "Set the networking mode for the RUN instructions during build")
+	flags.StringVar(&options.target, "target", "", "Set the target build stage to build.")
 
 	command.AddTrustedFlags(flags, true)
 
@@ -307,6 +309,7 @@ func runBuild(dockerCli *command.DockerCli, options buildOptions) error {
 		SecurityOpt:    options.securityOpt,
 		NetworkMode:    options.networkMode,
 		Squash:         options.squash,
+		Target:         options.target,
 	}
 
 	response, err := dockerCli.Client().ImageBuild(ctx, body, buildOptions)
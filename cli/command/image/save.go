@@ -6,14 +6,17 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/cli"
 	"github.com/docker/docker/cli/command"
 	"github.com/spf13/cobra"
 )
 
 type saveOptions struct {
-	images []string
-	output string
+	images            []string
+	output            string
+	format            string
+	excludeLayersFrom []string
 }
 
 // NewSaveCommand creates a new `docker save` command
@@ -33,6 +36,10 @@ func NewSaveCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVar(&opts.format, "format", types.ImageSaveFormatDocker, "Format of the archive (docker|oci)")
+	flags.SetAnnotation("format", "version", []string{"1.26"})
+	flags.StringSliceVar(&opts.excludeLayersFrom, "exclude-layers-from", []string{}, "Leave out the layers of these images, which the target already has")
+	flags.SetAnnotation("exclude-layers-from", "version", []string{"1.26"})
 
 	return cmd
 }
@@ -42,7 +49,14 @@ func runSave(dockerCli *command.DockerCli, opts saveOptions) error {
 		return errors.New("Cowardly refusing to save to a terminal. Use the -o flag or redirect.")
 	}
 
-	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images)
+	saveOpts := types.ImageSaveOptions{
+		ExcludeLayersFrom: opts.excludeLayersFrom,
+	}
+	if opts.format != types.ImageSaveFormatDocker {
+		saveOpts.Format = opts.format
+	}
+
+	responseBody, err := dockerCli.Client().ImageSaveWithOptions(context.Background(), opts.images, saveOpts)
 	if err != nil {
 		return err
 	}
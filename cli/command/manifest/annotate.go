@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cli/command"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type annotateOptions struct {
+	arch       string
+	os         string
+	osFeatures []string
+	variant    string
+}
+
+func newAnnotateCommand(dockerCli *command.DockerCli) *cobra.Command {
+	var opts annotateOptions
+
+	cmd := &cobra.Command{
+		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
+		Short: "Add platform information to a local image manifest",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAnnotate(cmd.Flags(), opts, args[0], args[1])
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.arch, "arch", "", "Set architecture")
+	flags.StringVar(&opts.os, "os", "", "Set operating system")
+	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system feature")
+	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	return cmd
+}
+
+func runAnnotate(flags *pflag.FlagSet, opts annotateOptions, list, image string) error {
+	listRef, err := parseRef(list)
+	if err != nil {
+		return err
+	}
+	ref, err := parseRef(image)
+	if err != nil {
+		return err
+	}
+
+	s := newStore()
+	m, err := s.get(listRef.String(), ref.String())
+	if err != nil {
+		return err
+	}
+	annotatePlatform(flags, opts, &m.Descriptor.Platform)
+	return s.save(listRef.String(), m)
+}
+
+// annotatePlatform sets the fields of platform that are given on the command
+// line, and leaves the others as they were found in the image.
+func annotatePlatform(flags *pflag.FlagSet, opts annotateOptions, platform *manifestlist.PlatformSpec) {
+	if flags.Changed("arch") {
+		platform.Architecture = opts.arch
+	}
+	if flags.Changed("os") {
+		platform.OS = opts.os
+	}
+	if flags.Changed("os-features") {
+		platform.OSFeatures = opts.osFeatures
+	}
+	if flags.Changed("variant") {
+		platform.Variant = opts.variant
+	}
+}
@@ -0,0 +1,18 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func TestAnnotatePlatform(t *testing.T) {
+	flags := newAnnotateCommand(nil).Flags()
+	flags.Set("arch", "arm")
+	flags.Set("variant", "v7")
+	platform := manifestlist.PlatformSpec{Architecture: "amd64", OS: "linux"}
+
+	annotatePlatform(flags, annotateOptions{arch: "arm", variant: "v7"}, &platform)
+	assert.DeepEqual(t, platform, manifestlist.PlatformSpec{Architecture: "arm", OS: "linux", Variant: "v7"})
+}
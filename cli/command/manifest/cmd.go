@@ -0,0 +1,25 @@
+package manifest
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cli/command"
+)
+
+// NewManifestCommand returns a cobra command for `manifest` subcommands
+func NewManifestCommand(dockerCli *command.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Manage Docker image manifests and manifest lists",
+		Args:  cli.NoArgs,
+		RunE:  dockerCli.ShowHelp,
+	}
+	cmd.AddCommand(
+		newCreateCommand(dockerCli),
+		newAnnotateCommand(dockerCli),
+		newInspectCommand(dockerCli),
+		newPushCommand(dockerCli),
+	)
+	return cmd
+}
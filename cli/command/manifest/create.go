@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cli/command"
+	"github.com/spf13/cobra"
+)
+
+type createOptions struct {
+	amend    bool
+	insecure bool
+}
+
+func newCreateCommand(dockerCli *command.DockerCli) *cobra.Command {
+	var opts createOptions
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] MANIFEST_LIST MANIFEST [MANIFEST...]",
+		Short: "Create a local manifest list for annotating and pushing to a registry",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreate(dockerCli, opts, args[0], args[1:])
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runCreate(dockerCli *command.DockerCli, opts createOptions, list string, images []string) error {
+	ctx := context.Background()
+
+	listRef, err := parseRef(list)
+	if err != nil {
+		return err
+	}
+
+	s := newStore()
+	exists, err := s.exists(listRef.String())
+	if err != nil {
+		return err
+	}
+	if exists && !opts.amend {
+		return fmt.Errorf("manifest list %s already exists, use --amend to add images to it", listRef.String())
+	}
+
+	// All the manifests are fetched before any is stored, so that the list is
+	// left as it was if one of them can't be added.
+	var manifests []imageManifest
+	for _, image := range images {
+		ref, err := parseRef(image)
+		if err != nil {
+			return err
+		}
+		if ref.Hostname() != listRef.Hostname() {
+			return fmt.Errorf("cannot add %s to %s: images of a manifest list must be in the same registry as the list", ref.String(), listRef.String())
+		}
+		m, err := getImageManifest(ctx, dockerCli, ref, opts.insecure)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, m)
+	}
+
+	for _, m := range manifests {
+		if err := s.save(listRef.String(), m); err != nil {
+			if !exists {
+				s.remove(listRef.String())
+			}
+			return err
+		}
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Created manifest list %s\n", listRef.String())
+	return nil
+}
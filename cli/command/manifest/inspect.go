@@ -0,0 +1,120 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cli/command"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	insecure bool
+}
+
+func newInspectCommand(dockerCli *command.DockerCli) *cobra.Command {
+	var opts inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] [MANIFEST_LIST] MANIFEST",
+		Short: "Display an image manifest, or manifest list",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				return runInspectLocalImage(dockerCli, args[0], args[1])
+			}
+			return runInspect(dockerCli, opts, args[0])
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+// runInspect displays the local manifest list name if there is one, and the
+// manifest of name in its registry otherwise.
+func runInspect(dockerCli *command.DockerCli, opts inspectOptions, name string) error {
+	ctx := context.Background()
+
+	ref, err := parseRef(name)
+	if err != nil {
+		return err
+	}
+
+	s := newStore()
+	exists, err := s.exists(ref.String())
+	if err != nil {
+		return err
+	}
+	if exists {
+		manifests, err := s.getList(ref.String())
+		if err != nil {
+			return err
+		}
+		list, err := buildManifestList(manifests)
+		if err != nil {
+			return err
+		}
+		_, payload, err := list.Payload()
+		if err != nil {
+			return err
+		}
+		return printJSON(dockerCli, payload)
+	}
+
+	repo, err := getRepository(ctx, dockerCli, ref, opts.insecure, "pull")
+	if err != nil {
+		return err
+	}
+	m, _, err := getManifest(ctx, repo, ref)
+	if err != nil {
+		return err
+	}
+	_, payload, err := m.Payload()
+	if err != nil {
+		return err
+	}
+	return printJSON(dockerCli, payload)
+}
+
+// runInspectLocalImage displays an image of a local manifest list, with the
+// platform it is annotated with.
+func runInspectLocalImage(dockerCli *command.DockerCli, list, image string) error {
+	listRef, err := parseRef(list)
+	if err != nil {
+		return err
+	}
+	ref, err := parseRef(image)
+	if err != nil {
+		return err
+	}
+
+	m, err := newStore().get(listRef.String(), ref.String())
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(struct {
+		Ref        string
+		Descriptor manifestlist.ManifestDescriptor
+		Manifest   json.RawMessage
+	}{m.Ref, m.Descriptor, m.Raw})
+	if err != nil {
+		return err
+	}
+	return printJSON(dockerCli, data)
+}
+
+func printJSON(dockerCli *command.DockerCli, data []byte) error {
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, "", "    "); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), b.String())
+	return nil
+}
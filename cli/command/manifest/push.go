@@ -0,0 +1,192 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/net/context"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	distreference "github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cli/command"
+	"github.com/docker/docker/reference"
+	"github.com/spf13/cobra"
+)
+
+type pushOptions struct {
+	purge    bool
+	insecure bool
+}
+
+func newPushCommand(dockerCli *command.DockerCli) *cobra.Command {
+	var opts pushOptions
+
+	cmd := &cobra.Command{
+		Use:   "push [OPTIONS] MANIFEST_LIST",
+		Short: "Push a manifest list to a repository",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPush(dockerCli, opts, args[0])
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.purge, "purge", "p", false, "Remove the local manifest list after push")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow push to an insecure registry")
+	return cmd
+}
+
+func runPush(dockerCli *command.DockerCli, opts pushOptions, list string) error {
+	ctx := context.Background()
+
+	listRef, err := parseRef(list)
+	if err != nil {
+		return err
+	}
+	tagged, ok := listRef.(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("cannot push manifest list %s: a manifest list must be pushed with a tag", listRef.String())
+	}
+
+	s := newStore()
+	manifests, err := s.getList(listRef.String())
+	if err != nil {
+		return err
+	}
+	manifestList, err := buildManifestList(manifests)
+	if err != nil {
+		return err
+	}
+
+	repo, err := getRepository(ctx, dockerCli, listRef, opts.insecure, "push", "pull")
+	if err != nil {
+		return err
+	}
+
+	// The images of a manifest list must be in its repository, so the images
+	// of other repositories are pushed to it first.
+	for _, m := range manifests {
+		ref, err := parseRef(m.Ref)
+		if err != nil {
+			return err
+		}
+		if ref.Name() == listRef.Name() {
+			continue
+		}
+		if err := pushImageManifest(ctx, dockerCli, repo, ref, m, opts.insecure); err != nil {
+			return fmt.Errorf("failed to push %s to %s: %v", ref.String(), listRef.Name(), err)
+		}
+	}
+
+	ms, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	dgst, err := ms.Put(ctx, manifestList, distribution.WithTag(tagged.Tag()))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), dgst.String())
+
+	if opts.purge {
+		return s.remove(listRef.String())
+	}
+	return nil
+}
+
+// buildManifestList returns the manifest list of the images manifests.
+func buildManifestList(manifests []imageManifest) (*manifestlist.DeserializedManifestList, error) {
+	descriptors := make([]manifestlist.ManifestDescriptor, 0, len(manifests))
+	for _, m := range manifests {
+		if m.Descriptor.Platform.Architecture == "" || m.Descriptor.Platform.OS == "" {
+			return nil, fmt.Errorf("manifest for image %s does not have an architecture and an operating system, set them with docker manifest annotate", m.Ref)
+		}
+		descriptors = append(descriptors, m.Descriptor)
+	}
+	return manifestlist.FromDescriptors(descriptors)
+}
+
+// pushImageManifest pushes the image manifest m of ref, which is in another
+// repository of the same registry, to repo. Its blobs are mounted from the
+// repository of ref when the registry allows it, and copied otherwise.
+func pushImageManifest(ctx context.Context, dockerCli *command.DockerCli, repo distribution.Repository, ref reference.Named, m imageManifest, insecure bool) error {
+	ms, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	exists, err := ms.Exists(ctx, m.Descriptor.Digest)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	var manifest schema2.DeserializedManifest
+	if err := manifest.UnmarshalJSON(m.Raw); err != nil {
+		return err
+	}
+
+	sourceRepo, err := getRepository(ctx, dockerCli, ref, insecure, "pull")
+	if err != nil {
+		return err
+	}
+	for _, desc := range manifest.References() {
+		if err := mountOrCopyBlob(ctx, repo, sourceRepo, ref, desc); err != nil {
+			return err
+		}
+	}
+
+	dgst, err := ms.Put(ctx, &manifest)
+	if err != nil {
+		return err
+	}
+	if dgst != m.Descriptor.Digest {
+		return fmt.Errorf("manifest digest %s does not match %s", dgst, m.Descriptor.Digest)
+	}
+	return nil
+}
+
+func mountOrCopyBlob(ctx context.Context, repo, sourceRepo distribution.Repository, sourceRef reference.Named, desc distribution.Descriptor) error {
+	bs := repo.Blobs(ctx)
+	if _, err := bs.Stat(ctx, desc.Digest); err == nil {
+		return nil
+	}
+
+	remoteRef, err := distreference.WithName(sourceRef.RemoteName())
+	if err != nil {
+		return err
+	}
+	canonicalRef, err := distreference.WithDigest(remoteRef, desc.Digest)
+	if err != nil {
+		return err
+	}
+	bw, err := bs.Create(ctx, client.WithMountFrom(canonicalRef))
+	switch err.(type) {
+	case nil:
+	case distribution.ErrBlobMounted:
+		logrus.Debugf("mounted blob %s from %s", desc.Digest, sourceRef.Name())
+		return nil
+	default:
+		return err
+	}
+
+	logrus.Debugf("copying blob %s from %s", desc.Digest, sourceRef.Name())
+	rc, err := sourceRepo.Blobs(ctx).Open(ctx, desc.Digest)
+	if err != nil {
+		bw.Cancel(ctx)
+		return err
+	}
+	defer rc.Close()
+	if _, err := io.Copy(bw, rc); err != nil {
+		bw.Cancel(ctx)
+		return err
+	}
+	_, err = bw.Commit(ctx, desc)
+	return err
+}
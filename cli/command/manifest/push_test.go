@@ -0,0 +1,19 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func TestBuildManifestList(t *testing.T) {
+	arm := newTestImageManifest("example.com/foo:arm", "arm")
+	list, err := buildManifestList([]imageManifest{arm})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, list.Manifests, []manifestlist.ManifestDescriptor{arm.Descriptor})
+
+	unknown := newTestImageManifest("example.com/foo:unknown", "")
+	_, err = buildManifestList([]imageManifest{arm, unknown})
+	assert.Error(t, err, "manifest for image example.com/foo:unknown does not have an architecture")
+}
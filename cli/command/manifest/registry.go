@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/cli/command"
+	dockerdist "github.com/docker/docker/distribution"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+)
+
+// parseRef parses a reference of an image or a manifest list, with the
+// default tag if it has neither a tag nor a digest.
+func parseRef(s string) (reference.Named, error) {
+	ref, err := reference.ParseNamed(s)
+	if err != nil {
+		return nil, err
+	}
+	return reference.WithDefaultTag(ref), nil
+}
+
+// getRepository returns a client for the repository of ref, using the
+// credentials of its registry in the configuration file of the CLI.
+func getRepository(ctx context.Context, dockerCli *command.DockerCli, ref reference.Named, insecure bool, actions ...string) (distribution.Repository, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := dockerdist.ValidateRepoName(repoInfo.Name()); err != nil {
+		return nil, err
+	}
+
+	options := registry.ServiceOptions{}
+	if insecure {
+		options.InsecureRegistries = []string{repoInfo.Index.Name}
+	}
+	endpoints, err := registry.NewService(options).LookupPushEndpoints(repoInfo.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig := command.ResolveAuthConfig(ctx, dockerCli, repoInfo.Index)
+	for _, endpoint := range endpoints {
+		if endpoint.Version != registry.APIVersion2 {
+			continue
+		}
+		repo, confirmedV2, err := dockerdist.NewV2Repository(ctx, repoInfo, endpoint, nil, &authConfig, actions...)
+		if err != nil {
+			logrus.Debugf("failed to connect to %s: %v", endpoint.URL, err)
+			continue
+		}
+		if !confirmedV2 {
+			continue
+		}
+		return repo, nil
+	}
+	return nil, fmt.Errorf("no v2 registry endpoint found for %s", repoInfo.FullName())
+}
+
+// getManifest fetches the manifest of ref, by tag or by digest, and returns
+// it with its digest.
+func getManifest(ctx context.Context, repo distribution.Repository, ref reference.Named) (distribution.Manifest, digest.Digest, error) {
+	ms, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var m distribution.Manifest
+	switch ref := ref.(type) {
+	case reference.Canonical:
+		m, err = ms.Get(ctx, ref.Digest())
+	case reference.NamedTagged:
+		m, err = ms.Get(ctx, "", distribution.WithTag(ref.Tag()))
+	default:
+		return nil, "", fmt.Errorf("internal error: reference has neither a tag nor a digest: %s", ref.String())
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if m == nil {
+		return nil, "", fmt.Errorf("manifest for %s does not exist", ref.String())
+	}
+
+	_, payload, err := m.Payload()
+	if err != nil {
+		return nil, "", err
+	}
+	return m, digest.FromBytes(payload), nil
+}
+
+// getImageManifest fetches the manifest of the image ref, and the platform
+// it runs on from its configuration.
+func getImageManifest(ctx context.Context, dockerCli *command.DockerCli, ref reference.Named, insecure bool) (imageManifest, error) {
+	repo, err := getRepository(ctx, dockerCli, ref, insecure, "pull")
+	if err != nil {
+		return imageManifest{}, err
+	}
+	m, dgst, err := getManifest(ctx, repo, ref)
+	if err != nil {
+		return imageManifest{}, err
+	}
+
+	var manifest *schema2.DeserializedManifest
+	switch m := m.(type) {
+	case *schema2.DeserializedManifest:
+		manifest = m
+	case *manifestlist.DeserializedManifestList:
+		return imageManifest{}, fmt.Errorf("%s is a manifest list, and cannot be added to another manifest list", ref.String())
+	default:
+		return imageManifest{}, fmt.Errorf("%s does not have a schema2 manifest, and cannot be added to a manifest list", ref.String())
+	}
+	if manifest.Config.MediaType != schema2.MediaTypeConfig {
+		return imageManifest{}, fmt.Errorf("%s is not an image", ref.String())
+	}
+
+	configJSON, err := repo.Blobs(ctx).Get(ctx, manifest.Config.Digest)
+	if err != nil {
+		return imageManifest{}, err
+	}
+	var config struct {
+		Architecture string   `json:"architecture"`
+		OS           string   `json:"os"`
+		OSVersion    string   `json:"os.version,omitempty"`
+		OSFeatures   []string `json:"os.features,omitempty"`
+		Variant      string   `json:"variant,omitempty"`
+	}
+	if err := json.Unmarshal(configJSON, &config); err != nil {
+		return imageManifest{}, fmt.Errorf("failed to read the configuration of %s: %v", ref.String(), err)
+	}
+
+	_, payload, err := manifest.Payload()
+	if err != nil {
+		return imageManifest{}, err
+	}
+	return imageManifest{
+		Ref: ref.String(),
+		Descriptor: manifestlist.ManifestDescriptor{
+			Descriptor: distribution.Descriptor{
+				MediaType: schema2.MediaTypeManifest,
+				Digest:    dgst,
+				Size:      int64(len(payload)),
+			},
+			Platform: manifestlist.PlatformSpec{
+				Architecture: config.Architecture,
+				OS:           config.OS,
+				OSVersion:    config.OSVersion,
+				OSFeatures:   config.OSFeatures,
+				Variant:      config.Variant,
+			},
+		},
+		Raw: payload,
+	}, nil
+}
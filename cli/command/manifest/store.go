@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+// imageManifest is an image of a manifest list, as kept in the local store
+// until the list is pushed.
+type imageManifest struct {
+	// Ref is the reference the image was added to the list with.
+	Ref string `json:"ref"`
+	// Descriptor is the entry of the image in the manifest list.
+	Descriptor manifestlist.ManifestDescriptor `json:"descriptor"`
+	// Raw is the image manifest, exactly as it was fetched from the
+	// registry, so that its digest does not change.
+	Raw []byte `json:"raw"`
+}
+
+// store keeps the manifest lists that are being assembled, with a directory
+// per manifest list and a file per image in it.
+type store struct {
+	root string
+}
+
+func newStore() *store {
+	return &store{root: filepath.Join(cliconfig.ConfigDir(), "manifests")}
+}
+
+func (s *store) listDir(listRef string) string {
+	return filepath.Join(s.root, url.QueryEscape(listRef))
+}
+
+// exists returns whether the manifest list listRef is in the store.
+func (s *store) exists(listRef string) (bool, error) {
+	_, err := os.Stat(s.listDir(listRef))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// get returns the image imageRef of the manifest list listRef.
+func (s *store) get(listRef, imageRef string) (imageManifest, error) {
+	var m imageManifest
+	data, err := ioutil.ReadFile(filepath.Join(s.listDir(listRef), url.QueryEscape(imageRef)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, fmt.Errorf("manifest for image %s does not exist in %s", imageRef, listRef)
+		}
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("failed to read manifest for image %s in %s: %v", imageRef, listRef, err)
+	}
+	return m, nil
+}
+
+// getList returns all the images of the manifest list listRef, sorted by
+// reference.
+func (s *store) getList(listRef string) ([]imageManifest, error) {
+	fis, err := ioutil.ReadDir(s.listDir(listRef))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("manifest list %s does not exist", listRef)
+		}
+		return nil, err
+	}
+
+	var manifests []imageManifest
+	for _, fi := range fis {
+		imageRef, err := url.QueryUnescape(fi.Name())
+		if err != nil {
+			continue
+		}
+		m, err := s.get(listRef, imageRef)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	sort.Sort(byRef(manifests))
+	return manifests, nil
+}
+
+// save adds or replaces the image m.Ref of the manifest list listRef.
+func (s *store) save(listRef string, m imageManifest) error {
+	dir := s.listDir(listRef)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(dir, url.QueryEscape(m.Ref)), data, 0600)
+}
+
+// remove removes the manifest list listRef and all its images.
+func (s *store) remove(listRef string) error {
+	return os.RemoveAll(s.listDir(listRef))
+}
+
+type byRef []imageManifest
+
+func (r byRef) Len() int           { return len(r) }
+func (r byRef) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byRef) Less(i, j int) bool { return r[i].Ref < r[j].Ref }
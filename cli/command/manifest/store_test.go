@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func newTestImageManifest(ref, arch string) imageManifest {
+	return imageManifest{
+		Ref: ref,
+		Descriptor: manifestlist.ManifestDescriptor{
+			Descriptor: distribution.Descriptor{
+				MediaType: "application/vnd.docker.distribution.manifest.v2+json",
+				Digest:    "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Size:      3,
+			},
+			Platform: manifestlist.PlatformSpec{Architecture: arch, OS: "linux"},
+		},
+		Raw: []byte("{ }"),
+	}
+}
+
+func TestStore(t *testing.T) {
+	root, err := ioutil.TempDir("", "manifest-store-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	s := &store{root: root}
+
+	exists, err := s.exists("example.com/foo:latest")
+	assert.NilError(t, err)
+	assert.Equal(t, exists, false)
+	_, err = s.getList("example.com/foo:latest")
+	assert.Error(t, err, "manifest list example.com/foo:latest does not exist")
+
+	arm := newTestImageManifest("example.com/foo:arm", "arm")
+	amd64 := newTestImageManifest("example.com/bar/foo:amd64", "amd64")
+	assert.NilError(t, s.save("example.com/foo:latest", arm))
+	assert.NilError(t, s.save("example.com/foo:latest", amd64))
+
+	exists, err = s.exists("example.com/foo:latest")
+	assert.NilError(t, err)
+	assert.Equal(t, exists, true)
+
+	m, err := s.get("example.com/foo:latest", "example.com/foo:arm")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, m, arm)
+	_, err = s.get("example.com/foo:latest", "example.com/foo:ppc64le")
+	assert.Error(t, err, "manifest for image example.com/foo:ppc64le does not exist in example.com/foo:latest")
+
+	manifests, err := s.getList("example.com/foo:latest")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, manifests, []imageManifest{amd64, arm})
+
+	assert.NilError(t, s.remove("example.com/foo:latest"))
+	exists, err = s.exists("example.com/foo:latest")
+	assert.NilError(t, err)
+	assert.Equal(t, exists, false)
+}
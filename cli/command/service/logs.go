@@ -13,30 +13,35 @@ import (
 	"github.com/docker/docker/cli"
 	"github.com/docker/docker/cli/command"
 	"github.com/docker/docker/cli/command/idresolver"
+	apiclient "github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/docker/pkg/stringid"
 	"github.com/spf13/cobra"
 )
 
 type logsOptions struct {
 	noResolve  bool
+	noTaskIDs  bool
+	raw        bool
 	follow     bool
 	since      string
+	until      string
 	timestamps bool
 	details    bool
 	tail       string
 
-	service string
+	target string
 }
 
 func newLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
 	var opts logsOptions
 
 	cmd := &cobra.Command{
-		Use:   "logs [OPTIONS] SERVICE",
-		Short: "Fetch the logs of a service",
+		Use:   "logs [OPTIONS] SERVICE|TASK",
+		Short: "Fetch the logs of a service or task",
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.service = args[0]
+			opts.target = args[0]
 			return runLogs(dockerCli, &opts)
 		},
 		Tags: map[string]string{"experimental": ""},
@@ -44,8 +49,14 @@ func newLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.noResolve, "no-resolve", false, "Do not map IDs to Names")
+	flags.BoolVar(&opts.noTaskIDs, "no-task-ids", false, "Do not include task IDs in output")
+	flags.SetAnnotation("no-task-ids", "version", []string{"1.26"})
+	flags.BoolVar(&opts.raw, "raw", false, "Do not prefix the output with the task context")
+	flags.SetAnnotation("raw", "version", []string{"1.26"})
 	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
 	flags.StringVar(&opts.since, "since", "", "Show logs since timestamp")
+	flags.StringVar(&opts.until, "until", "", "Show logs before timestamp")
+	flags.SetAnnotation("until", "version", []string{"1.26"})
 	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
 	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
 	flags.StringVar(&opts.tail, "tail", "all", "Number of lines to show from the end of the logs")
@@ -59,6 +70,7 @@ func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 		ShowStdout: true,
 		ShowStderr: true,
 		Since:      opts.since,
+		Until:      opts.until,
 		Timestamps: opts.timestamps,
 		Follow:     opts.follow,
 		Tail:       opts.tail,
@@ -66,7 +78,22 @@ func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 	}
 
 	client := dockerCli.Client()
-	responseBody, err := client.ServiceLogs(ctx, opts.service, options)
+
+	// The target is either a service, or a single task of a service.
+	var responseBody io.ReadCloser
+	service, _, err := client.ServiceInspectWithRaw(ctx, opts.target)
+	if err == nil {
+		responseBody, err = client.ServiceLogs(ctx, service.ID, options)
+	} else if apiclient.IsErrServiceNotFound(err) {
+		task, _, taskErr := client.TaskInspectWithRaw(ctx, opts.target)
+		if taskErr != nil {
+			if apiclient.IsErrTaskNotFound(taskErr) {
+				return fmt.Errorf("Error: No such service or task: %s", opts.target)
+			}
+			return taskErr
+		}
+		responseBody, err = client.TaskLogs(ctx, task.ID, options)
+	}
 	if err != nil {
 		return err
 	}
@@ -102,27 +129,35 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 		return 0, fmt.Errorf("invalid context in log message: %v", string(buf))
 	}
 
-	taskName, nodeName, err := lw.parseContext(string(parts[contextIndex]))
-	if err != nil {
-		return 0, err
-	}
-
 	output := []byte{}
-	for i, part := range parts {
-		// First part doesn't get space separation.
-		if i > 0 {
-			output = append(output, []byte(" ")...)
+	if lw.opts.raw {
+		// Only the message, and its timestamp if asked for, is written.
+		if lw.opts.timestamps {
+			output = append(output, parts[0]...)
+			output = append(output, ' ')
+		}
+		output = append(output, parts[numParts-1]...)
+	} else {
+		taskName, nodeName, err := lw.parseContext(string(parts[contextIndex]))
+		if err != nil {
+			return 0, err
 		}
 
-		if i == contextIndex {
-			// TODO(aluzzardi): Consider constant padding.
-			output = append(output, []byte(fmt.Sprintf("%s@%s    |", taskName, nodeName))...)
-		} else {
-			output = append(output, part...)
+		for i, part := range parts {
+			// First part doesn't get space separation.
+			if i > 0 {
+				output = append(output, []byte(" ")...)
+			}
+
+			if i == contextIndex {
+				// TODO(aluzzardi): Consider constant padding.
+				output = append(output, []byte(fmt.Sprintf("%s@%s    |", taskName, nodeName))...)
+			} else {
+				output = append(output, part...)
+			}
 		}
 	}
-	_, err = lw.w.Write(output)
-	if err != nil {
+	if _, err := lw.w.Write(output); err != nil {
 		return 0, err
 	}
 
@@ -149,6 +184,10 @@ func (lw *logWriter) parseContext(input string) (string, string, error) {
 	if err != nil {
 		return "", "", err
 	}
+	if lw.opts.noTaskIDs {
+		// resolved task names end with the truncated task ID
+		taskName = strings.TrimSuffix(taskName, "."+stringid.TruncateID(taskID))
+	}
 
 	nodeID, ok := context["com.docker.swarm.node.id"]
 	if !ok {
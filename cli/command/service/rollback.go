@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cli/command"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newRollbackCommand(dockerCli *command.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback [OPTIONS] SERVICE",
+		Short: "Revert changes to a service's configuration",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRollback(dockerCli, cmd.Flags(), args[0])
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Bool(flagRegistryAuth, false, "Send registry authentication details to swarm agents")
+
+	return cmd
+}
+
+// runRollback runs "docker service update --rollback", so that both commands
+// behave the same.
+func runRollback(dockerCli *command.DockerCli, flags *pflag.FlagSet, serviceID string) error {
+	updateFlags, err := rollbackUpdateFlags(dockerCli, flags)
+	if err != nil {
+		return err
+	}
+	return runUpdate(dockerCli, updateFlags, serviceID)
+}
+
+// rollbackUpdateFlags returns the flags of "docker service update" that
+// roll back a service with the given rollback flags.
+func rollbackUpdateFlags(dockerCli *command.DockerCli, flags *pflag.FlagSet) (*pflag.FlagSet, error) {
+	updateFlags := newUpdateCommand(dockerCli).Flags()
+	if err := updateFlags.Set("rollback", "true"); err != nil {
+		return nil, err
+	}
+	if flags.Changed(flagRegistryAuth) {
+		if err := updateFlags.Set(flagRegistryAuth, flags.Lookup(flagRegistryAuth).Value.String()); err != nil {
+			return nil, err
+		}
+	}
+	return updateFlags, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func TestRollbackUpdateFlags(t *testing.T) {
+	flags := newRollbackCommand(nil).Flags()
+
+	updateFlags, err := rollbackUpdateFlags(nil, flags)
+	assert.NilError(t, err)
+	rollback, err := updateFlags.GetBool("rollback")
+	assert.NilError(t, err)
+	assert.Equal(t, rollback, true)
+	sendAuth, err := updateFlags.GetBool(flagRegistryAuth)
+	assert.NilError(t, err)
+	assert.Equal(t, sendAuth, false)
+
+	flags.Set(flagRegistryAuth, "true")
+	updateFlags, err = rollbackUpdateFlags(nil, flags)
+	assert.NilError(t, err)
+	sendAuth, err = updateFlags.GetBool(flagRegistryAuth)
+	assert.NilError(t, err)
+	assert.Equal(t, sendAuth, true)
+}
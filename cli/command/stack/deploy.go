@@ -373,14 +373,19 @@ func convertVolumeToMount(
 		}, nil
 	}
 
-	stackVolume, exists := stackVolumes[source]
+	name := stackVolumeName(source)
+	stackVolume, exists := stackVolumes[name]
 	if !exists {
-		return mount.Mount{}, fmt.Errorf("undefined volume: %s", source)
+		return mount.Mount{}, fmt.Errorf("undefined volume: %s", name)
 	}
 
+	// keep the template of the source, if any, so that it gets expanded
+	// for every task
+	template := source[len(name):]
+
 	var volumeOptions *mount.VolumeOptions
 	if stackVolume.External.Name != "" {
-		source = stackVolume.External.Name
+		source = stackVolume.External.Name + template
 	} else {
 		volumeOptions = &mount.VolumeOptions{
 			Labels: getStackLabels(namespace.name, stackVolume.Labels),
@@ -393,7 +398,7 @@ func convertVolumeToMount(
 				Options: stackVolume.DriverOpts,
 			}
 		}
-		source = namespace.scope(source)
+		source = namespace.scope(name) + template
 	}
 	return mount.Mount{
 		Type:          mount.TypeVolume,
@@ -404,6 +409,19 @@ func convertVolumeToMount(
 	}, nil
 }
 
+// stackVolumeName returns the name of the stack volume that a volume source
+// refers to. A source can contain a template, like "data-{{.Task.Slot}}", to
+// get a volume per task. It then refers to the stack volume named after the
+// part of the source that comes before the template, without the trailing
+// separator ("data").
+func stackVolumeName(source string) string {
+	i := strings.Index(source, "{{")
+	if i < 0 {
+		return source
+	}
+	return strings.TrimRight(source[:i], "-_.")
+}
+
 func modeHas(mode []string, field string) bool {
 	for _, item := range mode {
 		if item == field {
@@ -0,0 +1,62 @@
+package stack
+
+import (
+	"testing"
+
+	composetypes "github.com/aanand/compose-file/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/testutil/assert"
+)
+
+func TestConvertVolumeToMountNamedVolume(t *testing.T) {
+	stackVolumes := map[string]composetypes.VolumeConfig{
+		"normal": {
+			Driver:     "glusterfs",
+			DriverOpts: map[string]string{"opt": "value"},
+		},
+	}
+	namespace := namespace{name: "foo"}
+	expected := mount.Mount{
+		Type:   mount.TypeVolume,
+		Source: "foo_normal",
+		Target: "/foo",
+		VolumeOptions: &mount.VolumeOptions{
+			Labels: map[string]string{"com.docker.stack.namespace": "foo"},
+			DriverConfig: &mount.Driver{
+				Name:    "glusterfs",
+				Options: map[string]string{"opt": "value"},
+			},
+		},
+	}
+	m, err := convertVolumeToMount("normal:/foo", stackVolumes, namespace)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, m, expected)
+}
+
+func TestConvertVolumeToMountTemplatedVolume(t *testing.T) {
+	stackVolumes := map[string]composetypes.VolumeConfig{
+		"data": {},
+		"outside": {
+			External: composetypes.External{External: true, Name: "special"},
+		},
+	}
+	namespace := namespace{name: "foo"}
+
+	m, err := convertVolumeToMount("data-{{.Task.Slot}}:/data", stackVolumes, namespace)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, m, mount.Mount{
+		Type:   mount.TypeVolume,
+		Source: "foo_data-{{.Task.Slot}}",
+		Target: "/data",
+		VolumeOptions: &mount.VolumeOptions{
+			Labels: map[string]string{"com.docker.stack.namespace": "foo"},
+		},
+	})
+
+	m, err = convertVolumeToMount("outside_{{.Task.ID}}:/data", stackVolumes, namespace)
+	assert.NilError(t, err)
+	assert.Equal(t, m.Source, "special_{{.Task.ID}}")
+
+	_, err = convertVolumeToMount("other-{{.Task.Slot}}:/data", stackVolumes, namespace)
+	assert.Error(t, err, "undefined volume: other")
+}
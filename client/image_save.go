@@ -5,14 +5,28 @@ import (
 	"net/url"
 
 	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/types"
 )
 
 // ImageSave retrieves one or more images from the docker host as an io.ReadCloser.
 // It's up to the caller to store the images and close the stream.
 func (cli *Client) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	return cli.ImageSaveWithOptions(ctx, imageIDs, types.ImageSaveOptions{})
+}
+
+// ImageSaveWithOptions is ImageSave with options, such as the format of the
+// archive.
+func (cli *Client) ImageSaveWithOptions(ctx context.Context, imageIDs []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 	query := url.Values{
 		"names": imageIDs,
 	}
+	if options.Format != "" {
+		query.Set("format", options.Format)
+	}
+	if len(options.ExcludeLayersFrom) > 0 {
+		query["excludeLayersFrom"] = options.ExcludeLayersFrom
+	}
 
 	resp, err := cli.get(ctx, "/images/get", query, nil)
 	if err != nil {
@@ -11,6 +11,8 @@ import (
 	"golang.org/x/net/context"
 
 	"strings"
+
+	"github.com/docker/docker/api/types"
 )
 
 func TestImageSaveError(t *testing.T) {
@@ -56,3 +58,54 @@ func TestImageSave(t *testing.T) {
 		t.Fatalf("expected response to contain 'response', got %s", string(response))
 	}
 }
+
+func TestImageSaveFormat(t *testing.T) {
+	expectedURL := "/images/get"
+	client := &Client{
+		client: newMockClient(func(r *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(r.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, r.URL)
+			}
+			format := r.URL.Query().Get("format")
+			if format != "oci" {
+				return nil, fmt.Errorf("format not set in URL query properly. Expected 'oci', got '%s'", format)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	saveResponse, err := client.ImageSaveWithOptions(context.Background(), []string{"image_id"}, types.ImageSaveOptions{Format: types.ImageSaveFormatOCI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveResponse.Close()
+}
+
+func TestImageSaveExcludeLayersFrom(t *testing.T) {
+	expectedURL := "/images/get"
+	client := &Client{
+		client: newMockClient(func(r *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(r.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, r.URL)
+			}
+			excludeLayersFrom := r.URL.Query()["excludeLayersFrom"]
+			expected := []string{"busybox", "alpine"}
+			if !reflect.DeepEqual(excludeLayersFrom, expected) {
+				return nil, fmt.Errorf("excludeLayersFrom not set in URL query properly. Expected %v, got %v", expected, excludeLayersFrom)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	saveResponse, err := client.ImageSaveWithOptions(context.Background(), []string{"image_id"}, types.ImageSaveOptions{ExcludeLayersFrom: []string{"busybox", "alpine"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveResponse.Close()
+}
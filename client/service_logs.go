@@ -14,6 +14,12 @@ import (
 // ServiceLogs returns the logs generated by a service in an io.ReadCloser.
 // It's up to the caller to close the stream.
 func (cli *Client) ServiceLogs(ctx context.Context, serviceID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	return cli.logs(ctx, "/services/"+serviceID+"/logs", options)
+}
+
+// logs returns the logs read from the swarm logs endpoint at path, for a
+// service or a task.
+func (cli *Client) logs(ctx context.Context, path string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
 	query := url.Values{}
 	if options.ShowStdout {
 		query.Set("stdout", "1")
@@ -31,6 +37,14 @@ func (cli *Client) ServiceLogs(ctx context.Context, serviceID string, options ty
 		query.Set("since", ts)
 	}
 
+	if options.Until != "" {
+		ts, err := timetypes.GetTimestamp(options.Until, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		query.Set("until", ts)
+	}
+
 	if options.Timestamps {
 		query.Set("timestamps", "1")
 	}
@@ -44,7 +58,7 @@ func (cli *Client) ServiceLogs(ctx context.Context, serviceID string, options ty
 	}
 	query.Set("tail", options.Tail)
 
-	resp, err := cli.get(ctx, "/services/"+serviceID+"/logs", query, nil)
+	resp, err := cli.get(ctx, path, query, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +24,8 @@ import (
 	"github.com/docker/docker/daemon/exec"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/docker/daemon/logger/local"
+	"github.com/docker/docker/daemon/logger/loggerutils/cache"
 	"github.com/docker/docker/daemon/network"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
@@ -48,6 +50,9 @@ import (
 
 const configFileName = "config.v2.json"
 
+// logCacheFile is the file the local log cache is stored in.
+const logCacheFile = "container-cached.log"
+
 const (
 	// DefaultStopTimeout is the timeout (in seconds) for the syscall signal used to stop a container.
 	DefaultStopTimeout = 10
@@ -349,7 +354,63 @@ func (container *Container) StartLogger() (logger.Logger, error) {
 			return nil, err
 		}
 	}
-	return c(ctx)
+
+	// Set logging file for the "local" driver
+	if cfg.Type == local.Name {
+		ctx.LogPath, err = container.GetRootResourcePath(filepath.Join("local-logs", "container.log"))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(ctx.LogPath), 0700); err != nil {
+			return nil, err
+		}
+	}
+	l, err := c(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep a local copy of the logs if the driver can't read them back
+	if _, ok := l.(logger.LogReader); !ok && cache.Enabled(cfg.Config) {
+		ctx.LogPath, err = container.GetRootResourcePath(logCacheFile)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		cl, err := cache.WithLocalCache(l, ctx)
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed to initialize the local log cache: %v", err)
+		}
+		l = cl
+	}
+	return l, nil
+}
+
+// OpenLogCache opens the local log cache of the container for reading, so
+// that logs can be read without creating the logging driver. It returns nil
+// if the cache is disabled or was never written for the container.
+func (container *Container) OpenLogCache() (logger.Logger, error) {
+	cfg := container.HostConfig.LogConfig
+	if !cache.Enabled(cfg.Config) {
+		return nil, nil
+	}
+	logPath, err := container.GetRootResourcePath(logCacheFile)
+	if err != nil {
+		return nil, err
+	}
+	// The cache only exists if the driver could not read the logs itself
+	if _, err := os.Stat(logPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cache.NewReader(logger.Context{
+		Config:      cfg.Config,
+		ContainerID: container.ID,
+		LogPath:     logPath,
+	})
 }
 
 // GetProcessLabel returns the process label for the container.
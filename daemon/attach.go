@@ -79,6 +79,15 @@ func (daemon *Daemon) containerAttach(c *container.Container, stdin io.ReadClose
 		if err != nil {
 			return err
 		}
+		if logDriver != c.LogDriver {
+			// The logger was started only to read the logs, as the container
+			// is not running, so it must not be leaked.
+			defer func() {
+				if err := logDriver.Close(); err != nil {
+					logrus.Errorf("Error closing logger: %v", err)
+				}
+			}()
+		}
 		cLog, ok := logDriver.(logger.LogReader)
 		if !ok {
 			return logger.ErrReadLogsNotSupported
@@ -60,6 +60,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	types "github.com/docker/docker/api/types/swarm"
+	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/daemon/cluster/convert"
 	executorpkg "github.com/docker/docker/daemon/cluster/executor"
 	"github.com/docker/docker/daemon/logger"
@@ -1076,6 +1077,41 @@ func (c *Cluster) RemoveService(input string) error {
 
 // ServiceLogs collects service logs and writes them back to `config.OutStream`
 func (c *Cluster) ServiceLogs(ctx context.Context, input string, config *backend.ContainerLogsConfig, started chan struct{}) error {
+	return c.logs(ctx, config, started, func(ctx context.Context, state nodeState) (*swarmapi.LogSelector, error) {
+		service, err := getService(ctx, state.controlClient, input)
+		if err != nil {
+			return nil, err
+		}
+		return &swarmapi.LogSelector{ServiceIDs: []string{service.ID}}, nil
+	})
+}
+
+// TaskLogs collects the logs of a single task and writes them back to
+// `config.OutStream`
+func (c *Cluster) TaskLogs(ctx context.Context, input string, config *backend.ContainerLogsConfig, started chan struct{}) error {
+	return c.logs(ctx, config, started, func(ctx context.Context, state nodeState) (*swarmapi.LogSelector, error) {
+		task, err := getTask(ctx, state.controlClient, input)
+		if err != nil {
+			return nil, err
+		}
+		return &swarmapi.LogSelector{TaskIDs: []string{task.ID}}, nil
+	})
+}
+
+// logs subscribes to the logs of the tasks returned by getSelector, and
+// writes them back to `config.OutStream`.
+func (c *Cluster) logs(ctx context.Context, config *backend.ContainerLogsConfig, started chan struct{}, getSelector func(context.Context, nodeState) (*swarmapi.LogSelector, error)) error {
+	// The end of the requested window can't be passed on to the agents, so
+	// the messages that come after it are dropped here.
+	var until time.Time
+	if config.Until != "" && config.Until != "0" {
+		s, n, err := timetypes.ParseTimestamps(config.Until, 0)
+		if err != nil {
+			return err
+		}
+		until = time.Unix(s, n)
+	}
+
 	c.mu.RLock()
 	state := c.currentNodeState()
 	if !state.IsActiveManager() {
@@ -1083,18 +1119,28 @@ func (c *Cluster) ServiceLogs(ctx context.Context, input string, config *backend
 		return c.errNoManager(state)
 	}
 
-	service, err := getService(ctx, state.controlClient, input)
+	selector, err := getSelector(ctx, state)
 	if err != nil {
 		c.mu.RUnlock()
 		return err
 	}
 
+	// Stop following once the end of the requested window is reached, even
+	// if no more messages are received.
+	var cancel context.CancelFunc
+	if config.Follow && !until.IsZero() {
+		ctx, cancel = context.WithDeadline(ctx, until)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
 	stream, err := state.logsClient.SubscribeLogs(ctx, &swarmapi.SubscribeLogsRequest{
-		Selector: &swarmapi.LogSelector{
-			ServiceIDs: []string{service.ID},
-		},
+		Selector: selector,
 		Options: &swarmapi.LogSubscriptionOptions{
-			Follow: config.Follow,
+			// There is nothing to follow if the requested window is
+			// already over.
+			Follow: config.Follow && (until.IsZero() || until.After(time.Now())),
 		},
 	})
 	if err != nil {
@@ -1116,6 +1162,9 @@ func (c *Cluster) ServiceLogs(ctx context.Context, input string, config *backend
 		// Check the context before doing anything.
 		select {
 		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil
+			}
 			return ctx.Err()
 		default:
 		}
@@ -1125,17 +1174,24 @@ func (c *Cluster) ServiceLogs(ctx context.Context, input string, config *backend
 			return nil
 		}
 		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil
+			}
 			return err
 		}
 
 		for _, msg := range subscribeMsg.Messages {
+			ts, err := ptypes.Timestamp(msg.Timestamp)
+			if err != nil {
+				return err
+			}
+			if !until.IsZero() && ts.After(until) {
+				continue
+			}
+
 			data := []byte{}
 
 			if config.Timestamps {
-				ts, err := ptypes.Timestamp(msg.Timestamp)
-				if err != nil {
-					return err
-				}
 				data = append(data, []byte(ts.Format(logger.TimeFormat)+" ")...)
 			}
 
@@ -0,0 +1,198 @@
+package cluster
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types/events"
+	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	swarmapi "github.com/docker/swarmkit/api"
+	"golang.org/x/net/context"
+)
+
+// clusterEventsInterval is the interval at which a manager looks for changes
+// of the swarm objects.
+const clusterEventsInterval = 2 * time.Second
+
+// clusterObject is the state of a swarm object that is compared between two
+// polls to find out what changed.
+type clusterObject struct {
+	version    uint64
+	attributes map[string]string
+}
+
+// clusterTask is the state of a task that is compared between two polls.
+type clusterTask struct {
+	serviceID string
+	nodeID    string
+	state     swarmapi.TaskState
+}
+
+type clusterSnapshot struct {
+	services map[string]clusterObject
+	nodes    map[string]clusterObject
+	secrets  map[string]clusterObject
+	tasks    map[string]clusterTask
+}
+
+// watchClusterEvents logs an event for every service, node and secret that
+// is created, updated or removed, and for every task that changes state,
+// until ctx is done.
+//
+// The control API has no way to watch the swarm objects, so they are polled
+// every clusterEventsInterval, and all the changes an object went through
+// between two polls are reported as a single event. Only the leader polls,
+// as the requests of the other managers are forwarded to it anyway.
+func watchClusterEvents(ctx context.Context, client swarmapi.ControlClient, nodeID string, backend executorpkg.Backend) {
+	ticker := time.NewTicker(clusterEventsInterval)
+	defer ticker.Stop()
+
+	var prev *clusterSnapshot
+	for {
+		snapshot, err := getLeaderSnapshot(ctx, client, nodeID)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			logrus.Debugf("failed to get swarm objects for cluster events: %v", err)
+		case snapshot == nil:
+			// Another manager is the leader. Start over if this one becomes
+			// the leader, rather than reporting what changed in between.
+			prev = nil
+		default:
+			if prev != nil {
+				logClusterEvents(backend, prev, snapshot)
+			}
+			prev = snapshot
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// getLeaderSnapshot returns the state of the swarm objects, or nil if the
+// node nodeID is not the leader.
+func getLeaderSnapshot(ctx context.Context, client swarmapi.ControlClient, nodeID string) (*clusterSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, swarmRequestTimeout)
+	defer cancel()
+
+	node, err := client.GetNode(ctx, &swarmapi.GetNodeRequest{NodeID: nodeID})
+	if err != nil {
+		return nil, err
+	}
+	if node.Node.ManagerStatus == nil || !node.Node.ManagerStatus.Leader {
+		return nil, nil
+	}
+
+	services, err := client.ListServices(ctx, &swarmapi.ListServicesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.ListNodes(ctx, &swarmapi.ListNodesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := client.ListSecrets(ctx, &swarmapi.ListSecretsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := client.ListTasks(ctx, &swarmapi.ListTasksRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &clusterSnapshot{
+		services: make(map[string]clusterObject, len(services.Services)),
+		nodes:    make(map[string]clusterObject, len(nodes.Nodes)),
+		secrets:  make(map[string]clusterObject, len(secrets.Secrets)),
+		tasks:    make(map[string]clusterTask, len(tasks.Tasks)),
+	}
+	for _, s := range services.Services {
+		snapshot.services[s.ID] = clusterObject{
+			version:    s.Meta.Version.Index,
+			attributes: map[string]string{"name": s.Spec.Annotations.Name},
+		}
+	}
+	for _, n := range nodes.Nodes {
+		name := n.Spec.Annotations.Name
+		if name == "" && n.Description != nil {
+			name = n.Description.Hostname
+		}
+		snapshot.nodes[n.ID] = clusterObject{
+			version: n.Meta.Version.Index,
+			attributes: map[string]string{
+				"name":         name,
+				"role":         strings.ToLower(n.Spec.Role.String()),
+				"availability": strings.ToLower(n.Spec.Availability.String()),
+				"state":        strings.ToLower(n.Status.State.String()),
+			},
+		}
+	}
+	for _, s := range secrets.Secrets {
+		snapshot.secrets[s.ID] = clusterObject{
+			version:    s.Meta.Version.Index,
+			attributes: map[string]string{"name": s.Spec.Annotations.Name},
+		}
+	}
+	for _, t := range tasks.Tasks {
+		snapshot.tasks[t.ID] = clusterTask{serviceID: t.ServiceID, nodeID: t.NodeID, state: t.Status.State}
+	}
+	return snapshot, nil
+}
+
+// logClusterEvents logs the events for the changes from prev to cur.
+func logClusterEvents(backend executorpkg.Backend, prev, cur *clusterSnapshot) {
+	logObjectEvents(backend, events.ServiceEventType, prev.services, cur.services)
+	logObjectEvents(backend, events.NodeEventType, prev.nodes, cur.nodes)
+	logObjectEvents(backend, events.SecretEventType, prev.secrets, cur.secrets)
+
+	// Task state changes are reported as events of their service, as tasks
+	// are replaced all the time and have no name to filter them on.
+	for id, t := range cur.tasks {
+		if p, ok := prev.tasks[id]; ok && p.state == t.state {
+			continue
+		}
+		attributes := map[string]string{"task.id": id}
+		if t.nodeID != "" {
+			attributes["node.id"] = t.nodeID
+		}
+		if s, ok := cur.services[t.serviceID]; ok {
+			attributes["name"] = s.attributes["name"]
+		}
+		backend.LogClusterEvent(events.ServiceEventType, t.serviceID, "task_state: "+strings.ToLower(t.state.String()), attributes)
+	}
+}
+
+func logObjectEvents(backend executorpkg.Backend, eventType string, prev, cur map[string]clusterObject) {
+	for id, o := range cur {
+		p, ok := prev[id]
+		switch {
+		case !ok:
+			backend.LogClusterEvent(eventType, id, "create", copyAttributes(o.attributes))
+		case p.version != o.version:
+			attributes := copyAttributes(o.attributes)
+			attributes["version"] = strconv.FormatUint(o.version, 10)
+			backend.LogClusterEvent(eventType, id, "update", attributes)
+		}
+	}
+	for id, p := range prev {
+		if _, ok := cur[id]; !ok {
+			backend.LogClusterEvent(eventType, id, "remove", copyAttributes(p.attributes))
+		}
+	}
+}
+
+func copyAttributes(attributes map[string]string) map[string]string {
+	c := make(map[string]string, len(attributes))
+	for k, v := range attributes {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,167 @@
+package cluster
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	executorpkg "github.com/docker/docker/daemon/cluster/executor"
+	swarmapi "github.com/docker/swarmkit/api"
+)
+
+type loggedEvent struct {
+	eventType  string
+	id         string
+	action     string
+	attributes map[string]string
+}
+
+// eventsBackend records the cluster events, the other methods of the backend
+// are not used.
+type eventsBackend struct {
+	executorpkg.Backend
+	events []loggedEvent
+}
+
+func (b *eventsBackend) LogClusterEvent(eventType, id, action string, attributes map[string]string) {
+	b.events = append(b.events, loggedEvent{eventType, id, action, attributes})
+}
+
+type byEvent []loggedEvent
+
+func (e byEvent) Len() int      { return len(e) }
+func (e byEvent) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e byEvent) Less(i, j int) bool {
+	if e[i].eventType != e[j].eventType {
+		return e[i].eventType < e[j].eventType
+	}
+	if e[i].id != e[j].id {
+		return e[i].id < e[j].id
+	}
+	return e[i].action < e[j].action
+}
+
+func newSnapshot() *clusterSnapshot {
+	return &clusterSnapshot{
+		services: make(map[string]clusterObject),
+		nodes:    make(map[string]clusterObject),
+		secrets:  make(map[string]clusterObject),
+		tasks:    make(map[string]clusterTask),
+	}
+}
+
+func TestLogClusterEvents(t *testing.T) {
+	web := clusterObject{version: 10, attributes: map[string]string{"name": "web"}}
+	webUpdated := clusterObject{version: 12, attributes: map[string]string{"name": "web"}}
+	worker := clusterObject{version: 3, attributes: map[string]string{"name": "worker", "role": "worker"}}
+	secret := clusterObject{version: 5, attributes: map[string]string{"name": "password"}}
+
+	testCases := []struct {
+		name     string
+		prev     func(*clusterSnapshot)
+		cur      func(*clusterSnapshot)
+		expected []loggedEvent
+	}{
+		{
+			name: "no changes",
+			prev: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+				s.tasks["task1"] = clusterTask{serviceID: "svc1", nodeID: "node1", state: swarmapi.TaskStateRunning}
+			},
+			cur: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+				s.tasks["task1"] = clusterTask{serviceID: "svc1", nodeID: "node1", state: swarmapi.TaskStateRunning}
+			},
+		},
+		{
+			name: "create",
+			prev: func(s *clusterSnapshot) {},
+			cur: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+				s.nodes["node1"] = worker
+				s.secrets["secret1"] = secret
+			},
+			expected: []loggedEvent{
+				{events.NodeEventType, "node1", "create", map[string]string{"name": "worker", "role": "worker"}},
+				{events.SecretEventType, "secret1", "create", map[string]string{"name": "password"}},
+				{events.ServiceEventType, "svc1", "create", map[string]string{"name": "web"}},
+			},
+		},
+		{
+			name: "update",
+			prev: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+				s.secrets["secret1"] = secret
+			},
+			cur: func(s *clusterSnapshot) {
+				s.services["svc1"] = webUpdated
+				s.secrets["secret1"] = secret
+			},
+			expected: []loggedEvent{
+				{events.ServiceEventType, "svc1", "update", map[string]string{"name": "web", "version": "12"}},
+			},
+		},
+		{
+			name: "remove",
+			prev: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+				s.nodes["node1"] = worker
+			},
+			cur: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+			},
+			expected: []loggedEvent{
+				{events.NodeEventType, "node1", "remove", map[string]string{"name": "worker", "role": "worker"}},
+			},
+		},
+		{
+			name: "task_state",
+			prev: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+				s.tasks["task1"] = clusterTask{serviceID: "svc1", nodeID: "node1", state: swarmapi.TaskStateRunning}
+			},
+			cur: func(s *clusterSnapshot) {
+				s.services["svc1"] = web
+				s.tasks["task1"] = clusterTask{serviceID: "svc1", nodeID: "node1", state: swarmapi.TaskStateFailed}
+				s.tasks["task2"] = clusterTask{serviceID: "svc1", state: swarmapi.TaskStatePending}
+			},
+			expected: []loggedEvent{
+				{events.ServiceEventType, "svc1", "task_state: failed", map[string]string{"name": "web", "node.id": "node1", "task.id": "task1"}},
+				{events.ServiceEventType, "svc1", "task_state: pending", map[string]string{"name": "web", "task.id": "task2"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		prev, cur := newSnapshot(), newSnapshot()
+		tc.prev(prev)
+		tc.cur(cur)
+
+		b := &eventsBackend{}
+		logClusterEvents(b, prev, cur)
+
+		// events are logged in map order
+		sort.Sort(byEvent(b.events))
+		if !reflect.DeepEqual(b.events, tc.expected) {
+			t.Fatalf("%s: expected events %v, got %v", tc.name, tc.expected, b.events)
+		}
+	}
+}
+
+func TestLogObjectEventsCopiesAttributes(t *testing.T) {
+	prev := map[string]clusterObject{}
+	cur := map[string]clusterObject{
+		"svc1": {version: 1, attributes: map[string]string{"name": "web"}},
+	}
+
+	b := &eventsBackend{}
+	logObjectEvents(b, events.ServiceEventType, prev, cur)
+	if len(b.events) != 1 {
+		t.Fatalf("expected 1 event, got %v", b.events)
+	}
+	b.events[0].attributes["name"] = "changed"
+	if cur["svc1"].attributes["name"] != "web" {
+		t.Fatal("expected the event attributes to be a copy of the object attributes")
+	}
+}
@@ -0,0 +1,94 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/swarmkit/api"
+)
+
+func TestVolumeCreateRequestTemplate(t *testing.T) {
+	c, err := newContainerConfig(&api.Task{
+		ID:        "taskid",
+		ServiceID: "serviceid",
+		NodeID:    "nodeid",
+		Slot:      2,
+		ServiceAnnotations: api.Annotations{
+			Name: "web",
+		},
+		Spec: api.TaskSpec{
+			Runtime: &api.TaskSpec_Container{
+				Container: &api.ContainerSpec{
+					Image: "image_name",
+					Mounts: []api.Mount{
+						{
+							Type:   api.MountTypeVolume,
+							Source: "data-{{.Task.Slot}}",
+							Target: testAbsPath,
+							VolumeOptions: &api.Mount_VolumeOptions{
+								DriverConfig: &api.Driver{
+									Name:    "rexray",
+									Options: map[string]string{"size": "{{.Service.Name}}"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mounts := c.spec().Mounts
+	if len(mounts) != 1 {
+		t.Fatalf("expected 1 mount, got %d", len(mounts))
+	}
+	req := c.volumeCreateRequest(&mounts[0])
+	if req == nil {
+		t.Fatal("expected a volume create request")
+	}
+	if req.Name != "data-2" {
+		t.Fatalf("expected volume name data-2, got %s", req.Name)
+	}
+	if req.Driver != "rexray" {
+		t.Fatalf("expected driver rexray, got %s", req.Driver)
+	}
+	if req.DriverOpts["size"] != "web" {
+		t.Fatalf("expected driver option size=web, got %s", req.DriverOpts["size"])
+	}
+}
+
+func TestContainerConfigTemplate(t *testing.T) {
+	c, err := newContainerConfig(&api.Task{
+		ID:        "taskid",
+		ServiceID: "serviceid",
+		NodeID:    "nodeid",
+		Slot:      2,
+		ServiceAnnotations: api.Annotations{
+			Name: "db",
+		},
+		Spec: api.TaskSpec{
+			Runtime: &api.TaskSpec_Container{
+				Container: &api.ContainerSpec{
+					Image:    "image_name",
+					Hostname: "{{.Service.Name}}-{{.Task.Slot}}",
+					Env:      []string{"NODE_ID={{.Node.ID}}", "TASK_ID={{.Task.ID}}"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := c.config()
+	if config.Hostname != "db-2" {
+		t.Fatalf("expected hostname db-2, got %s", config.Hostname)
+	}
+	expectedEnv := []string{"NODE_ID=nodeid", "TASK_ID=taskid"}
+	if !reflect.DeepEqual(config.Env, expectedEnv) {
+		t.Fatalf("expected env %v, got %v", expectedEnv, config.Env)
+	}
+}
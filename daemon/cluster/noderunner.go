@@ -134,15 +134,26 @@ func (n *nodeRunner) start(conf nodeStartConfig) error {
 }
 
 func (n *nodeRunner) handleControlSocketChange(ctx context.Context, node *swarmnode.Node) {
+	cancelEvents := func() {}
+	defer func() {
+		cancelEvents()
+	}()
+
 	for conn := range node.ListenControlSocket(ctx) {
 		n.mu.Lock()
 		if n.grpcConn != conn {
+			cancelEvents()
 			if conn == nil {
 				n.controlClient = nil
 				n.logsClient = nil
 			} else {
 				n.controlClient = swarmapi.NewControlClient(conn)
 				n.logsClient = swarmapi.NewLogsClient(conn)
+
+				// the leader reports the changes of the swarm objects as events
+				var eventsCtx context.Context
+				eventsCtx, cancelEvents = context.WithCancel(ctx)
+				go watchClusterEvents(eventsCtx, n.controlClient, node.NodeID(), n.cluster.config.Backend)
 			}
 		}
 		n.grpcConn = conn
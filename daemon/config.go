@@ -47,10 +47,11 @@ const (
 // Use this to differentiate these options
 // with others like the ones in CommonTLSOptions.
 var flatOptions = map[string]bool{
-	"cluster-store-opts": true,
-	"log-opts":           true,
-	"runtimes":           true,
-	"default-ulimits":    true,
+	"cluster-store-opts":   true,
+	"log-opts":             true,
+	"runtimes":             true,
+	"default-ulimits":      true,
+	"registry-mirrors-for": true,
 }
 
 // LogConfig represents the default log configuration.
@@ -497,6 +498,11 @@ func ValidateConfiguration(config *Config) error {
 		}
 	}
 
+	// validate the mirrors of registries other than Docker Hub
+	if _, err := registry.ValidateRegistryMirrors(config.RegistryMirrors); err != nil {
+		return err
+	}
+
 	// validate MaxConcurrentDownloads
 	if config.IsValueSet("max-concurrent-downloads") && config.MaxConcurrentDownloads != nil && *config.MaxConcurrentDownloads < 0 {
 		return fmt.Errorf("invalid max concurrent downloads: %d", *config.MaxConcurrentDownloads)
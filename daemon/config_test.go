@@ -3,6 +3,7 @@ package daemon
 import (
 	"io/ioutil"
 	"os"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -227,3 +228,40 @@ func TestValidateConfiguration(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestDaemonConfigurationRegistryMirrors(t *testing.T) {
+	f, err := ioutil.TempFile("", "docker-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := f.Name()
+	defer os.Remove(configFile)
+
+	f.Write([]byte(`{"registry-mirrors-for": {"myregistry:5000": ["https://mirror.example.com"]}}`))
+	f.Close()
+
+	c := &Config{}
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c.ServiceOptions.InstallCliFlags(flags)
+
+	expected := map[string][]string{"myregistry:5000": {"https://mirror.example.com"}}
+
+	cc, err := MergeDaemonConfigurations(c, flags, configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cc.RegistryMirrors, expected) {
+		t.Fatalf("expected registry mirrors %v, got %v", expected, cc.RegistryMirrors)
+	}
+
+	var reloaded *Config
+	if err := ReloadConfiguration(configFile, flags, func(c *Config) { reloaded = c }); err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.IsValueSet("registry-mirrors-for") {
+		t.Fatal("expected registry-mirrors-for to be set after a reload")
+	}
+	if !reflect.DeepEqual(reloaded.RegistryMirrors, expected) {
+		t.Fatalf("expected registry mirrors %v, got %v", expected, reloaded.RegistryMirrors)
+	}
+}
@@ -237,6 +237,12 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 				return nil, err
 			}
 		}
+
+		if config.Healthcheck != nil {
+			if err := validateHealthcheck(config.Healthcheck); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if hostConfig == nil {
@@ -247,6 +253,12 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 		return nil, fmt.Errorf("can't create 'AutoRemove' container with restart policy")
 	}
 
+	switch hostConfig.OnUnhealthy {
+	case "", "none", "restart", "stop":
+	default:
+		return nil, fmt.Errorf("invalid on-unhealthy action '%s'", hostConfig.OnUnhealthy)
+	}
+
 	for port := range hostConfig.PortBindings {
 		_, portStr := nat.SplitProtoPort(string(port))
 		if _, err := nat.ParsePort(portStr); err != nil {
@@ -28,11 +28,13 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/events"
 	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/plugin"
 	"github.com/docker/libnetwork/cluster"
 	// register graph drivers
 	_ "github.com/docker/docker/daemon/graphdriver/register"
+	"github.com/docker/docker/distribution"
 	dmetadata "github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
@@ -72,6 +74,10 @@ var (
 	errSystemNotSupported = fmt.Errorf("The Docker daemon is not supported on this platform.")
 )
 
+// partialDownloadTimeout is how long the data of an interrupted layer
+// download is kept for a later pull to resume it.
+const partialDownloadTimeout = 24 * time.Hour
+
 // Daemon holds information about the Docker daemon.
 type Daemon struct {
 	ID                        string
@@ -80,6 +86,7 @@ type Daemon struct {
 	execCommands              *exec.Store
 	referenceStore            reference.Store
 	downloadManager           *xfer.LayerDownloadManager
+	partialDownloadStore      *distribution.PartialDownloadStore
 	uploadManager             *xfer.LayerUploadManager
 	distributionMetadataStore dmetadata.Store
 	trustKey                  libtrust.PrivateKey
@@ -550,6 +557,7 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	if err := d.pluginInit(config, containerdRemote); err != nil {
 		return nil, err
 	}
+	logger.RegisterPluginGetter(d.PluginStore)
 
 	d.layerStore, err = layer.NewStoreFromOptions(layer.StoreOptions{
 		StorePath:                 config.Root,
@@ -574,6 +582,10 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 
 	logrus.Debugf("Max Concurrent Downloads: %d", *config.MaxConcurrentDownloads)
 	d.downloadManager = xfer.NewLayerDownloadManager(d.layerStore, *config.MaxConcurrentDownloads)
+	d.partialDownloadStore, err = distribution.NewPartialDownloadStore(filepath.Join(imageRoot, "downloads"), partialDownloadTimeout)
+	if err != nil {
+		return nil, err
+	}
 	logrus.Debugf("Max Concurrent Uploads: %d", *config.MaxConcurrentUploads)
 	d.uploadManager = xfer.NewLayerUploadManager(*config.MaxConcurrentUploads)
 
@@ -997,6 +1009,7 @@ func (daemon *Daemon) initDiscovery(config *Config) error {
 // - Daemon labels.
 // - Daemon debug log level.
 // - Daemon insecure registries.
+// - Daemon registry mirrors of registries other than Docker Hub.
 // - Daemon max concurrent downloads
 // - Daemon max concurrent uploads
 // - Cluster discovery (reconfigure and restart).
@@ -1034,6 +1047,12 @@ func (daemon *Daemon) Reload(config *Config) (err error) {
 			return err
 		}
 	}
+	if config.IsValueSet("registry-mirrors-for") {
+		daemon.configStore.RegistryMirrors = config.RegistryMirrors
+		if err := daemon.RegistryService.LoadRegistryMirrors(config.RegistryMirrors); err != nil {
+			return err
+		}
+	}
 	if config.IsValueSet("live-restore") {
 		daemon.configStore.LiveRestoreEnabled = config.LiveRestoreEnabled
 		if err := daemon.containerdRemote.UpdateOptions(libcontainerd.WithLiveRestore(config.LiveRestoreEnabled)); err != nil {
@@ -1086,6 +1105,16 @@ func (daemon *Daemon) Reload(config *Config) (err error) {
 		attributes["insecure-registries"] = "[]"
 	}
 
+	if daemon.configStore.RegistryMirrors != nil {
+		registryMirrors, err := json.Marshal(daemon.configStore.RegistryMirrors)
+		if err != nil {
+			return err
+		}
+		attributes["registry-mirrors-for"] = string(registryMirrors)
+	} else {
+		attributes["registry-mirrors-for"] = "{}"
+	}
+
 	attributes["cluster-store"] = daemon.configStore.ClusterStore
 	if daemon.configStore.ClusterOpts != nil {
 		opts, err := json.Marshal(daemon.configStore.ClusterOpts)
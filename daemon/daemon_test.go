@@ -427,6 +427,51 @@ func TestDaemonReloadInsecureRegistries(t *testing.T) {
 	}
 }
 
+func TestDaemonReloadRegistryMirrors(t *testing.T) {
+	daemon := &Daemon{}
+	daemon.RegistryService = registry.NewService(registry.ServiceOptions{
+		RegistryMirrors: map[string][]string{
+			"registry.example.com": {"https://mirror1.example.com"},
+		},
+	})
+	daemon.configStore = &Config{}
+
+	registryMirrors := map[string][]string{
+		"registry.example.com": {"https://mirror2.example.com", "https://mirror3.example.com"},
+	}
+
+	valuesSets := make(map[string]interface{})
+	valuesSets["registry-mirrors-for"] = registryMirrors
+
+	newConfig := &Config{
+		CommonConfig: CommonConfig{
+			ServiceOptions: registry.ServiceOptions{
+				RegistryMirrors: registryMirrors,
+			},
+			valuesSet: valuesSets,
+		},
+	}
+
+	if err := daemon.Reload(newConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoints, err := daemon.RegistryService.LookupPullEndpoints("registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mirrors []string
+	for _, endpoint := range endpoints {
+		if endpoint.Mirror {
+			mirrors = append(mirrors, endpoint.URL.Host)
+		}
+	}
+	expected := []string{"mirror2.example.com", "mirror3.example.com"}
+	if !reflect.DeepEqual(mirrors, expected) {
+		t.Fatalf("Expected mirrors %v, got %v", expected, mirrors)
+	}
+}
+
 func TestDaemonReloadNotAffectOthers(t *testing.T) {
 	daemon := &Daemon{}
 	daemon.configStore = &Config{
@@ -70,6 +70,16 @@ func (daemon *Daemon) LogPluginEventWithAttributes(pluginID, refName, action str
 	daemon.EventsService.Log(action, events.PluginEventType, actor)
 }
 
+// LogClusterEvent generates an event related to a swarm object, such as a
+// service, a node or a secret.
+func (daemon *Daemon) LogClusterEvent(eventType, id, action string, attributes map[string]string) {
+	actor := events.Actor{
+		ID:         id,
+		Attributes: attributes,
+	}
+	daemon.EventsService.Log(action, eventType, actor)
+}
+
 // LogVolumeEvent generates an event related to a volume.
 func (daemon *Daemon) LogVolumeEvent(volumeID, action string, attributes map[string]string) {
 	actor := events.Actor{
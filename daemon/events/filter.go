@@ -26,13 +26,16 @@ func (ef *Filter) Include(ev events.Message) bool {
 		ef.matchVolume(ev) &&
 		ef.matchNetwork(ev) &&
 		ef.matchImage(ev) &&
+		ef.matchService(ev) &&
+		ef.matchNode(ev) &&
+		ef.matchSecret(ev) &&
 		ef.matchLabels(ev.Actor.Attributes)
 }
 
 func (ef *Filter) matchEvent(ev events.Message) bool {
-	// #25798 if an event filter contains either health_status, exec_create or exec_start without a colon
+	// #25798 if an event filter contains either health_status, exec_create, exec_start or task_state without a colon
 	// Let's to a FuzzyMatch instead of an ExactMatch.
-	if ef.filterContains("event", map[string]struct{}{"health_status": {}, "exec_create": {}, "exec_start": {}}) {
+	if ef.filterContains("event", map[string]struct{}{"health_status": {}, "exec_create": {}, "exec_start": {}, "task_state": {}}) {
 		return ef.filter.FuzzyMatch("event", ev.Action)
 	}
 	return ef.filter.ExactMatch("event", ev.Action)
@@ -74,6 +77,22 @@ func (ef *Filter) matchNetwork(ev events.Message) bool {
 	return ef.fuzzyMatchName(ev, events.NetworkEventType)
 }
 
+func (ef *Filter) matchService(ev events.Message) bool {
+	return ef.fuzzyMatchName(ev, events.ServiceEventType)
+}
+
+// matchNode matches against both event.Actor.ID (for node events) and
+// event.Actor.Attributes["node.id"] (for the task events of services), so
+// that the tasks running on a node are included in the node events.
+func (ef *Filter) matchNode(ev events.Message) bool {
+	return ef.fuzzyMatchName(ev, events.NodeEventType) ||
+		ef.filter.FuzzyMatch(events.NodeEventType, ev.Actor.Attributes["node.id"])
+}
+
+func (ef *Filter) matchSecret(ev events.Message) bool {
+	return ef.fuzzyMatchName(ev, events.SecretEventType)
+}
+
 func (ef *Filter) fuzzyMatchName(ev events.Message, eventType string) bool {
 	return ef.filter.FuzzyMatch(eventType, ev.Actor.ID) ||
 		ef.filter.FuzzyMatch(eventType, ev.Actor.Attributes["name"])
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+func TestFilterClusterEvents(t *testing.T) {
+	serviceCreate := events.Message{
+		Type:   events.ServiceEventType,
+		Action: "create",
+		Actor:  events.Actor{ID: "serviceid", Attributes: map[string]string{"name": "web"}},
+	}
+	taskState := events.Message{
+		Type:   events.ServiceEventType,
+		Action: "task_state: running",
+		Actor:  events.Actor{ID: "serviceid", Attributes: map[string]string{"name": "web", "task.id": "taskid", "node.id": "nodeid"}},
+	}
+	nodeUpdate := events.Message{
+		Type:   events.NodeEventType,
+		Action: "update",
+		Actor:  events.Actor{ID: "nodeid", Attributes: map[string]string{"name": "worker1"}},
+	}
+	secretRemove := events.Message{
+		Type:   events.SecretEventType,
+		Action: "remove",
+		Actor:  events.Actor{ID: "secretid", Attributes: map[string]string{"name": "password"}},
+	}
+	all := []events.Message{serviceCreate, taskState, nodeUpdate, secretRemove}
+
+	for _, tc := range []struct {
+		filters  map[string][]string
+		expected []events.Message
+	}{
+		{map[string][]string{}, all},
+		{map[string][]string{"type": {"service"}}, []events.Message{serviceCreate, taskState}},
+		{map[string][]string{"type": {"node", "secret"}}, []events.Message{nodeUpdate, secretRemove}},
+		{map[string][]string{"service": {"web"}}, []events.Message{serviceCreate, taskState}},
+		{map[string][]string{"node": {"worker1"}}, []events.Message{nodeUpdate}},
+		// the tasks running on a node are part of the node events
+		{map[string][]string{"node": {"nodeid"}}, []events.Message{taskState, nodeUpdate}},
+		{map[string][]string{"secret": {"secretid"}}, []events.Message{secretRemove}},
+		{map[string][]string{"event": {"task_state"}}, []events.Message{taskState}},
+		{map[string][]string{"type": {"service"}, "event": {"create"}}, []events.Message{serviceCreate}},
+	} {
+		args := filters.NewArgs()
+		for k, values := range tc.filters {
+			for _, v := range values {
+				args.Add(k, v)
+			}
+		}
+		ef := NewFilter(args)
+
+		var got []events.Message
+		for _, ev := range all {
+			if ef.Include(ev) {
+				got = append(got, ev)
+			}
+		}
+		if len(got) != len(tc.expected) {
+			t.Fatalf("filters %v: expected %d events, got %d: %v", tc.filters, len(tc.expected), len(got), got)
+		}
+		for i := range got {
+			if got[i].Type != tc.expected[i].Type || got[i].Action != tc.expected[i].Action {
+				t.Fatalf("filters %v: expected %v, got %v", tc.filters, tc.expected[i], got[i])
+			}
+		}
+	}
+}
@@ -2,13 +2,20 @@ package daemon
 
 import (
 	"bytes"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/types"
@@ -34,8 +41,17 @@ const (
 	// for the container to be considered unhealthy.
 	defaultProbeRetries = 3
 
+	// Default time during which failed probes don't count, after the
+	// container started.
+	defaultStartPeriod = 0 * time.Second
+
 	// Maximum number of entries to record
 	maxLogEntries = 5
+
+	// Range of the HTTP status codes that are considered healthy, if the
+	// healthcheck doesn't set one.
+	defaultHTTPStatusMin = 200
+	defaultHTTPStatusMax = 399
 )
 
 const (
@@ -103,6 +119,185 @@ func (p *cmdProbe) run(ctx context.Context, d *Daemon, container *container.Cont
 	}, nil
 }
 
+// httpProbe implements the "HTTP" probe type.
+type httpProbe struct{}
+
+// get the URL of the healthcheck from the container's network namespace.
+// The container is healthy if the status code of the response is in the
+// expected range.
+func (p *httpProbe) run(ctx context.Context, d *Daemon, c *container.Container) (*types.HealthcheckResult, error) {
+	u, statusMin, statusMax, err := parseHTTPProbe(c.Config.Healthcheck.Test[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(network, addr string) (net.Conn, error) {
+				return dialInContainer(ctx, c, network, addr)
+			},
+			DisableKeepAlives: true,
+			// The probe checks that the service responds, not who it is.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		// Redirects are reported as they are, they may be in the expected range.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := ctxhttp.Get(ctx, client, u.String())
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	defer resp.Body.Close()
+
+	output := &limitedBuffer{}
+	fmt.Fprintf(output, "HTTP GET %s: %s\n", u, resp.Status)
+	io.Copy(output, io.LimitReader(resp.Body, maxOutputLen))
+
+	exitCode := exitStatusHealthy
+	if resp.StatusCode < statusMin || resp.StatusCode > statusMax {
+		exitCode = exitStatusUnhealthy
+	}
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitCode,
+		Output:   output.String(),
+	}, nil
+}
+
+// tcpProbe implements the "TCP" probe type.
+type tcpProbe struct{}
+
+// connect to the address of the healthcheck from the container's network
+// namespace. The container is healthy if the connection is accepted.
+func (p *tcpProbe) run(ctx context.Context, d *Daemon, c *container.Container) (*types.HealthcheckResult, error) {
+	addr, err := parseTCPProbe(c.Config.Healthcheck.Test[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialInContainer(ctx, c, "tcp", addr)
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	conn.Close()
+
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitStatusHealthy,
+		Output:   fmt.Sprintf("TCP connection to %s succeeded", addr),
+	}, nil
+}
+
+// parseHTTPProbe parses the arguments of an "HTTP" healthcheck: the URL to
+// get, and an optional range of the status codes that are considered
+// healthy, such as "200-299" or "204".
+func parseHTTPProbe(args []string) (u *url.URL, statusMin, statusMax int, err error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, 0, 0, fmt.Errorf("HTTP healthcheck takes a URL and an optional range of status codes")
+	}
+
+	u, err = url.Parse(args[0])
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid HTTP healthcheck URL %q: %v", args[0], err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, 0, 0, fmt.Errorf("invalid HTTP healthcheck URL %q: scheme must be http or https", args[0])
+	}
+	if u.Host == "" {
+		return nil, 0, 0, fmt.Errorf("invalid HTTP healthcheck URL %q: missing host", args[0])
+	}
+	host := u.Host
+	if h, _, err := net.SplitHostPort(u.Host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if _, err := probeHostIPs(host); err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid HTTP healthcheck URL %q: %v", args[0], err)
+	}
+
+	statusMin, statusMax = defaultHTTPStatusMin, defaultHTTPStatusMax
+	if len(args) == 2 {
+		parts := strings.SplitN(args[1], "-", 2)
+		statusMin, err = strconv.Atoi(parts[0])
+		if err == nil {
+			statusMax = statusMin
+			if len(parts) == 2 {
+				statusMax, err = strconv.Atoi(parts[1])
+			}
+		}
+		if err != nil || statusMin < 100 || statusMax > 599 || statusMin > statusMax {
+			return nil, 0, 0, fmt.Errorf("invalid HTTP healthcheck status range %q", args[1])
+		}
+	}
+	return u, statusMin, statusMax, nil
+}
+
+// parseTCPProbe parses the argument of a "TCP" healthcheck, which is either
+// a port on the loopback interface of the container, or a "host:port"
+// address.
+func parseTCPProbe(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("TCP healthcheck takes exactly one address")
+	}
+
+	host, port, err := net.SplitHostPort(args[0])
+	if err != nil {
+		host, port = "localhost", args[0]
+	}
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return "", fmt.Errorf("invalid TCP healthcheck address %q", args[0])
+	}
+	if _, err := probeHostIPs(host); err != nil {
+		return "", fmt.Errorf("invalid TCP healthcheck address %q: %v", args[0], err)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// probeHostIPs returns the addresses to connect to for the host of an HTTP or
+// TCP probe. Names would be resolved by the daemon and not in the container,
+// so only "localhost" and IP addresses are accepted.
+func probeHostIPs(host string) ([]net.IP, error) {
+	if host == "localhost" {
+		return []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}, nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	return nil, fmt.Errorf("host %q must be localhost or an IP address", host)
+}
+
+// validateHealthcheck checks the arguments of the probe types whose syntax
+// is known to the daemon.
+func validateHealthcheck(config *containertypes.HealthConfig) error {
+	if len(config.Test) == 0 {
+		return nil
+	}
+	switch config.Test[0] {
+	case "HTTP", "TCP":
+		if !daemonProbesSupported {
+			return fmt.Errorf("%s health checks are not supported on this platform", config.Test[0])
+		}
+		if config.Test[0] == "HTTP" {
+			_, _, _, err := parseHTTPProbe(config.Test[1:])
+			return err
+		}
+		_, err := parseTCPProbe(config.Test[1:])
+		return err
+	}
+	return nil
+}
+
 // Update the container's Status.Health struct based on the latest probe's result.
 func handleProbeResult(d *Daemon, c *container.Container, result *types.HealthcheckResult, done chan struct{}) {
 	c.Lock()
@@ -134,15 +329,56 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 		h.Status = types.Healthy
 	} else {
 		// Failure (including invalid exit code)
-		h.FailingStreak++
-		if h.FailingStreak >= retries {
-			h.Status = types.Unhealthy
+		shouldIncrementStreak := true
+
+		// If the container never had a successful health check, failures
+		// don't count until the start period is over.
+		if h.Status == types.Starting {
+			startPeriod := timeoutWithDefault(c.Config.Healthcheck.StartPeriod, defaultStartPeriod)
+			if result.Start.Sub(c.State.StartedAt) < startPeriod {
+				shouldIncrementStreak = false
+			}
+		}
+
+		if shouldIncrementStreak {
+			h.FailingStreak++
+			if h.FailingStreak >= retries {
+				h.Status = types.Unhealthy
+			}
 		}
 		// Else we're starting or healthy. Stay in that state.
 	}
 
 	if oldStatus != h.Status {
 		d.LogContainerEvent(c, "health_status: "+h.Status)
+		if h.Status == types.Unhealthy {
+			d.handleUnhealthy(c)
+		}
+	}
+}
+
+// handleUnhealthy takes the action set by the container's OnUnhealthy policy,
+// once the container became unhealthy.
+// Called with c locked.
+func (d *Daemon) handleUnhealthy(c *container.Container) {
+	if c.HostConfig == nil {
+		return
+	}
+	switch c.HostConfig.OnUnhealthy {
+	case "restart":
+		logrus.Infof("Restarting unhealthy container %s", c.ID)
+		go func() {
+			if err := d.containerRestart(c, c.StopTimeout()); err != nil {
+				logrus.Errorf("Failed to restart unhealthy container %s: %v", c.ID, err)
+			}
+		}()
+	case "stop":
+		logrus.Infof("Stopping unhealthy container %s", c.ID)
+		go func() {
+			if err := d.containerStop(c, c.StopTimeout()); err != nil {
+				logrus.Errorf("Failed to stop unhealthy container %s: %v", c.ID, err)
+			}
+		}()
 	}
 }
 
@@ -219,8 +455,12 @@ func getProbe(c *container.Container) probe {
 		return &cmdProbe{shell: false}
 	case "CMD-SHELL":
 		return &cmdProbe{shell: true}
+	case "HTTP":
+		return &httpProbe{}
+	case "TCP":
+		return &tcpProbe{}
 	default:
-		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD') in container %s", config.Test[0], c.ID)
+		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD', 'HTTP' or 'TCP') in container %s", config.Test[0], c.ID)
 		return nil
 	}
 }
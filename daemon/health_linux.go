@@ -0,0 +1,89 @@
+// +build linux
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+
+	"golang.org/x/net/context"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/vishvananda/netns"
+)
+
+// daemonProbesSupported tells whether the daemon can run HTTP and TCP probes.
+const daemonProbesSupported = true
+
+// dialInContainer connects to addr from the network namespace of the
+// container. The host of addr must be localhost or an IP address, which are
+// reached as a process running in the container would reach them. Names are
+// not resolved, as the daemon doesn't use the DNS server or the /etc/hosts
+// file of the container.
+func dialInContainer(ctx context.Context, c *container.Container, network, addr string) (net.Conn, error) {
+	pid := c.State.GetPID()
+	if pid == 0 {
+		return nil, fmt.Errorf("container %s is not running", c.ID)
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := probeHostIPs(host)
+	if err != nil {
+		return nil, err
+	}
+
+	// The namespace is a property of the thread, so the goroutine must not
+	// move to another thread while the socket is created.
+	runtime.LockOSThread()
+
+	origns, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+	defer origns.Close()
+
+	ns, err := netns.GetFromPid(pid)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("failed to get the network namespace of container %s: %v", c.ID, err)
+	}
+	defer ns.Close()
+
+	if err := netns.Set(ns); err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("failed to enter the network namespace of container %s: %v", c.ID, err)
+	}
+
+	conn, dialErr := dialIPs(ctx, network, ips, port)
+
+	if err := netns.Set(origns); err != nil {
+		// Leave the thread locked, so that it is never reused by other
+		// goroutines while it is in the namespace of the container.
+		logrus.Errorf("Failed to restore the network namespace after health check of container %s: %v", c.ID, err)
+	} else {
+		runtime.UnlockOSThread()
+	}
+	return conn, dialErr
+}
+
+// dialIPs connects to the first of ips that accepts the connection. Only IP
+// literals are dialed, one after the other, so that the socket is created by
+// the calling goroutine, on its locked thread.
+func dialIPs(ctx context.Context, network string, ips []net.IP, port string) (net.Conn, error) {
+	d := net.Dialer{FallbackDelay: -1}
+	err := fmt.Errorf("no address to connect to")
+	for _, ip := range ips {
+		var conn net.Conn
+		conn, err = d.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
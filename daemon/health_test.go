@@ -116,3 +116,120 @@ func TestHealthStates(t *testing.T) {
 		t.Errorf("Expecting FailingStreak=0, but got %d\n", c.State.Health.FailingStreak)
 	}
 }
+
+func TestParseHTTPProbe(t *testing.T) {
+	u, statusMin, statusMax, err := parseHTTPProbe([]string{"http://localhost:8080/health"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "http://localhost:8080/health" || statusMin != 200 || statusMax != 399 {
+		t.Fatalf("unexpected result %s %d-%d", u, statusMin, statusMax)
+	}
+
+	if _, _, _, err := parseHTTPProbe([]string{"http://[::1]/health"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, statusMin, statusMax, err = parseHTTPProbe([]string{"https://127.0.0.1/", "204"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statusMin != 204 || statusMax != 204 {
+		t.Fatalf("unexpected status range %d-%d", statusMin, statusMax)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"localhost:8080"},
+		{"ftp://localhost/"},
+		{"http:///health"},
+		{"http://localhost/", "foo"},
+		{"http://localhost/", "299-200"},
+		{"http://localhost/", "200-700"},
+		{"http://localhost/", "200", "extra"},
+		{"http://db:8080/health"},
+		{"http://example.com/"},
+	} {
+		if _, _, _, err := parseHTTPProbe(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestParseTCPProbe(t *testing.T) {
+	for arg, expected := range map[string]string{
+		"8080":          "localhost:8080",
+		"10.0.0.1:5432": "10.0.0.1:5432",
+		"[::1]:443":     "[::1]:443",
+	} {
+		addr, err := parseTCPProbe([]string{arg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != expected {
+			t.Errorf("expected %s for %s, got %s", expected, arg, addr)
+		}
+	}
+
+	for _, args := range [][]string{{}, {"foo"}, {"0"}, {"localhost:70000"}, {"1", "2"}, {"db:5432"}} {
+		if _, err := parseTCPProbe(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestHealthStartPeriod(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:   "container_id",
+			Name: "container_name",
+			Config: &containertypes.Config{
+				Image: "image_name",
+				Healthcheck: &containertypes.HealthConfig{
+					Retries:     1,
+					StartPeriod: 30 * time.Second,
+				},
+			},
+		},
+	}
+	daemon := &Daemon{
+		EventsService: e,
+	}
+
+	reset(c)
+
+	handleResult := func(startTime time.Time, exitCode int) {
+		handleProbeResult(daemon, c, &types.HealthcheckResult{
+			Start:    startTime,
+			End:      startTime,
+			ExitCode: exitCode,
+		}, nil)
+	}
+
+	// failures in the start period don't count
+	handleResult(c.State.StartedAt.Add(10*time.Second), 1)
+	if c.State.Health.Status != types.Starting {
+		t.Errorf("Expecting starting, but got %#v\n", c.State.Health.Status)
+	}
+	if c.State.Health.FailingStreak != 0 {
+		t.Errorf("Expecting FailingStreak=0, but got %d\n", c.State.Health.FailingStreak)
+	}
+
+	// once the start period is over, they do
+	handleResult(c.State.StartedAt.Add(40*time.Second), 1)
+	if c.State.Health.Status != types.Unhealthy {
+		t.Errorf("Expecting unhealthy, but got %#v\n", c.State.Health.Status)
+	}
+
+	// a success ends the start period
+	reset(c)
+	handleResult(c.State.StartedAt.Add(5*time.Second), 0)
+	handleResult(c.State.StartedAt.Add(10*time.Second), 1)
+	if c.State.Health.Status != types.Unhealthy {
+		t.Errorf("Expecting unhealthy, but got %#v\n", c.State.Health.Status)
+	}
+}
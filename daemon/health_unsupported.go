@@ -0,0 +1,19 @@
+// +build !linux
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/container"
+)
+
+// daemonProbesSupported tells whether the daemon can run HTTP and TCP probes.
+const daemonProbesSupported = false
+
+func dialInContainer(ctx context.Context, c *container.Container, network, addr string) (net.Conn, error) {
+	return nil, fmt.Errorf("HTTP and TCP health checks are not supported on this platform")
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/plugins/logdriver"
+)
+
+// mockLoggingPlugin implements the logPlugin interface. It keeps the encoded
+// entries it receives, and sends them back when the logs are read.
+type mockLoggingPlugin struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	started string
+	stopped string
+	done    chan struct{}
+	in      io.Reader
+}
+
+func (l *mockLoggingPlugin) StartLogging(file string, ctx Context) error {
+	l.started = file
+	l.done = make(chan struct{})
+	go func() {
+		defer close(l.done)
+		dec := logdriver.NewLogEntryDecoder(l.in)
+		for {
+			var entry logdriver.LogEntry
+			if err := dec.Decode(&entry); err != nil {
+				return
+			}
+			l.mu.Lock()
+			logdriver.NewLogEntryEncoder(&l.buf).Encode(&entry)
+			l.mu.Unlock()
+		}
+	}()
+	return nil
+}
+
+func (l *mockLoggingPlugin) StopLogging(file string) error {
+	l.stopped = file
+	return nil
+}
+
+func (l *mockLoggingPlugin) Capabilities() (Capability, error) {
+	return Capability{ReadLogs: true}, nil
+}
+
+func (l *mockLoggingPlugin) ReadLogs(ctx Context, config ReadConfig) (io.ReadCloser, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ioutil.NopCloser(bytes.NewReader(l.buf.Bytes())), nil
+}
+
+func newMockPluginAdapter(t *testing.T) (*pluginAdapterWithRead, *mockLoggingPlugin) {
+	r, w := io.Pipe()
+	plugin := &mockLoggingPlugin{in: r}
+	a := &pluginAdapter{
+		driverName: "mock",
+		plugin:     plugin,
+		basePath:   "/plugin/rootfs",
+		fifoPath:   "/plugin/rootfs/run/docker/logging/1234",
+		stream:     w,
+		enc:        logdriver.NewLogEntryEncoder(w),
+	}
+	if err := plugin.StartLogging("/run/docker/logging/1234", a.ctx); err != nil {
+		t.Fatal(err)
+	}
+	return &pluginAdapterWithRead{a}, plugin
+}
+
+func TestAdapterReadLogs(t *testing.T) {
+	l, plugin := newMockPluginAdapter(t)
+
+	now := time.Now()
+	testMsgs := []Message{
+		{Source: "stdout", Timestamp: now.Add(-time.Minute), Line: []byte("first")},
+		{Source: "stderr", Timestamp: now.Add(-30 * time.Second), Line: []byte("second")},
+		{Source: "stdout", Timestamp: now, Line: []byte("partial"), Partial: true},
+	}
+	for i := range testMsgs {
+		if err := l.Log(&testMsgs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	<-plugin.done
+	if plugin.stopped != plugin.started {
+		t.Fatalf("expected logging to be stopped for %s, got %s", plugin.started, plugin.stopped)
+	}
+
+	lw := l.ReadLogs(ReadConfig{Since: now.Add(-45 * time.Second)})
+	var msgs []*Message
+	for msg := range lw.Msg {
+		msgs = append(msgs, msg)
+	}
+	select {
+	case err := <-lw.Err:
+		t.Fatal(err)
+	default:
+	}
+
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if string(msgs[0].Line) != "second\n" || msgs[0].Source != "stderr" || !msgs[0].Timestamp.Equal(testMsgs[1].Timestamp) {
+		t.Fatalf("unexpected message %+v", msgs[0])
+	}
+	if string(msgs[1].Line) != "partial" || !msgs[1].Partial {
+		t.Fatalf("unexpected message %+v", msgs[1])
+	}
+}
@@ -3,6 +3,8 @@ package logger
 import (
 	"fmt"
 	"sync"
+
+	getter "github.com/docker/docker/pkg/plugingetter"
 )
 
 // Creator builds a logging driver instance with given context.
@@ -52,8 +54,13 @@ func (lf *logdriverFactory) get(name string) (Creator, error) {
 	defer lf.m.Unlock()
 
 	c, ok := lf.registry[name]
-	if !ok {
-		return c, fmt.Errorf("logger: no log driver named '%s' is registered", name)
+	if ok {
+		return c, nil
+	}
+
+	c, err := getPlugin(name, getter.ACQUIRE)
+	if err != nil {
+		return nil, fmt.Errorf("logger: no log driver named '%s' is registered: %v", name, err)
 	}
 	return c, nil
 }
@@ -68,6 +75,32 @@ func (lf *logdriverFactory) getLogOptValidator(name string) LogOptValidator {
 
 var factory = &logdriverFactory{registry: make(map[string]Creator), optValidator: make(map[string]LogOptValidator)} // global factory instance
 
+var (
+	builtInLogOpts     = make(map[string]bool)
+	externalValidators []LogOptValidator
+	builtInMu          sync.Mutex
+)
+
+// AddBuiltinLogOpts registers log options that are handled by the daemon
+// for every logging driver, rather than by the driver itself. They are
+// removed from the options passed to the driver's validator.
+func AddBuiltinLogOpts(opts map[string]bool) {
+	builtInMu.Lock()
+	for k, v := range opts {
+		builtInLogOpts[k] = v
+	}
+	builtInMu.Unlock()
+}
+
+// RegisterExternalValidator adds a validator that is run against the
+// options of every logging driver, in addition to the driver's own
+// validator.
+func RegisterExternalValidator(v LogOptValidator) {
+	builtInMu.Lock()
+	externalValidators = append(externalValidators, v)
+	builtInMu.Unlock()
+}
+
 // RegisterLogDriver registers the given logging driver builder with given logging
 // driver name.
 func RegisterLogDriver(name string, c Creator) error {
@@ -93,12 +126,30 @@ func ValidateLogOpts(name string, cfg map[string]string) error {
 	}
 
 	if !factory.driverRegistered(name) {
-		return fmt.Errorf("logger: no log driver named '%s' is registered", name)
+		if _, err := getPlugin(name, getter.LOOKUP); err != nil {
+			return fmt.Errorf("logger: no log driver named '%s' is registered: %v", name, err)
+		}
+	}
+
+	builtInMu.Lock()
+	validators := externalValidators
+	filteredOpts := make(map[string]string, len(cfg))
+	for k, v := range cfg {
+		if !builtInLogOpts[k] {
+			filteredOpts[k] = v
+		}
+	}
+	builtInMu.Unlock()
+
+	for _, v := range validators {
+		if err := v(cfg); err != nil {
+			return err
+		}
 	}
 
 	validator := factory.getLogOptValidator(name)
 	if validator != nil {
-		return validator(cfg)
+		return validator(filteredOpts)
 	}
 	return nil
 }
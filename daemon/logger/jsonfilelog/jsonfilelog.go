@@ -61,7 +61,19 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		}
 	}
 
-	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capval, maxFiles)
+	var compress bool
+	if compressString, ok := ctx.Config["compress"]; ok {
+		var err error
+		compress, err = strconv.ParseBool(compressString)
+		if err != nil {
+			return nil, err
+		}
+		if compress && maxFiles < 2 {
+			return nil, fmt.Errorf("compress cannot be true when max-file is less than 2")
+		}
+	}
+
+	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capval, maxFiles, compress)
 	if err != nil {
 		return nil, err
 	}
@@ -113,12 +125,21 @@ func (l *JSONFileLogger) Log(msg *logger.Message) error {
 	return err
 }
 
-// ValidateLogOpt looks for json specific log options max-file & max-size.
+// ValidateLogOpt looks for json specific log options max-file, max-size & compress.
 func ValidateLogOpt(cfg map[string]string) error {
 	for key := range cfg {
 		switch key {
 		case "max-file":
 		case "max-size":
+		case "compress":
+			compress, err := strconv.ParseBool(cfg[key])
+			if err != nil {
+				return fmt.Errorf("invalid value %q for log opt 'compress' of json-file log driver: %v", cfg[key], err)
+			}
+			// max-file defaults to 1, and has no rotated files to compress
+			if maxFiles, _ := strconv.Atoi(cfg["max-file"]); compress && maxFiles < 2 {
+				return fmt.Errorf("compress cannot be true when max-file is less than 2")
+			}
 		case "labels":
 		case "env":
 		default:
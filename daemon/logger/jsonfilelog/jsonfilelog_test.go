@@ -152,6 +152,67 @@ func TestJSONFileLoggerWithOpts(t *testing.T) {
 
 }
 
+func TestJSONFileLoggerWithCompress(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	config := map[string]string{"max-file": "3", "max-size": "1k", "compress": "true"}
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+		Config:      config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	for i := 0; i < 36; i++ {
+		if err := l.Log(&logger.Message{Line: []byte("line" + strconv.Itoa(i)), Source: "src1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// wait for the background compression to be done
+	l.(*JSONFileLogger).writer.Close()
+
+	for _, name := range []string{filename + ".1.gz", filename + ".2.gz"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Fatalf("expected rotated file to be compressed: %v", err)
+		}
+	}
+	if _, err := os.Stat(filename + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("expected uncompressed rotated file to be removed, got %v", err)
+	}
+
+	lw := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	var i int
+	for msg := range lw.Msg {
+		if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+			t.Fatalf("Wrong log line: %q, expected %q", msg.Line, expected)
+		}
+		i++
+	}
+	if i != 36 {
+		t.Fatalf("Expected 36 log lines, got %d", i)
+	}
+
+	// the tail goes back into the compressed files
+	lw = l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: 30})
+	i = 6
+	for msg := range lw.Msg {
+		if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+			t.Fatalf("Wrong log line: %q, expected %q", msg.Line, expected)
+		}
+		i++
+	}
+	if i != 36 {
+		t.Fatalf("Expected 30 log lines, got %d", i-6)
+	}
+}
+
 func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
 	tmp, err := ioutil.TempDir("", "docker-logger-")
@@ -246,3 +307,65 @@ func BenchmarkJSONFileLoggerWithReader(b *testing.B) {
 		}
 	}
 }
+
+func TestValidateLogOptCompress(t *testing.T) {
+	valid := []map[string]string{
+		{"compress": "false"},
+		{"compress": "true", "max-file": "2"},
+	}
+	for _, cfg := range valid {
+		if err := ValidateLogOpt(cfg); err != nil {
+			t.Fatalf("expected %v to be valid, got %v", cfg, err)
+		}
+	}
+	invalid := []map[string]string{
+		{"compress": "maybe", "max-file": "2"},
+		{"compress": "true"},
+		{"compress": "true", "max-file": "1"},
+	}
+	for _, cfg := range invalid {
+		if err := ValidateLogOpt(cfg); err == nil {
+			t.Fatalf("expected %v to be invalid", cfg)
+		}
+	}
+}
+
+func TestJSONFileLoggerTailUntil(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+		Config:      map[string]string{"max-file": "3", "max-size": "1k"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 36; i++ {
+		msg := &logger.Message{Line: []byte("line" + strconv.Itoa(i)), Source: "src1", Timestamp: start.Add(time.Duration(i) * time.Second)}
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the tail is counted from until, which is in a rotated file
+	lw := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: 5, Until: start.Add(10 * time.Second)})
+	i := 6
+	for msg := range lw.Msg {
+		if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+			t.Fatalf("Wrong log line: %q, expected %q", msg.Line, expected)
+		}
+		i++
+	}
+	if i != 11 {
+		t.Fatalf("Expected 5 log lines, got %d", i-6)
+	}
+}
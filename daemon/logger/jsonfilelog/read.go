@@ -14,8 +14,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/daemon/logger"
-	"github.com/docker/docker/pkg/filenotify"
-	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/daemon/logger/loggerutils"
 	"github.com/docker/docker/pkg/jsonlog"
 	"github.com/docker/docker/pkg/tailfile"
 )
@@ -53,21 +52,6 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.mu.Lock()
 
 	pth := l.writer.LogPath()
-	var files []io.ReadSeeker
-	for i := l.writer.MaxFiles(); i > 1; i-- {
-		f, err := os.Open(fmt.Sprintf("%s.%d", pth, i-1))
-		if err != nil {
-			if !os.IsNotExist(err) {
-				logWatcher.Err <- err
-				break
-			}
-			continue
-		}
-		defer f.Close()
-
-		files = append(files, f)
-	}
-
 	latestFile, err := os.Open(pth)
 	if err != nil {
 		logWatcher.Err <- err
@@ -76,16 +60,14 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	}
 	defer latestFile.Close()
 
-	if config.Tail != 0 {
-		tailer := ioutils.MultiReadSeeker(append(files, latestFile)...)
-		tailFile(tailer, logWatcher, config.Tail, config.Since)
+	files := &logFiles{path: pth, maxFiles: l.writer.MaxFiles(), latest: latestFile}
+	if config.Tail > 0 {
+		err = tailFiles(files, logWatcher, config.Tail, config.Since, config.Until)
+	} else if config.Tail < 0 {
+		err = readFiles(files, logWatcher, config.Since, config.Until)
 	}
-
-	// close all the rotated files
-	for _, f := range files {
-		if err := f.(io.Closer).Close(); err != nil {
-			logrus.WithField("logger", "json-file").Warnf("error closing tailed log file: %v", err)
-		}
+	if err != nil {
+		logWatcher.Err <- err
 	}
 
 	if !config.Follow {
@@ -104,7 +86,7 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.mu.Unlock()
 
 	notifyRotate := l.writer.NotifyRotate()
-	followLogs(latestFile, logWatcher, notifyRotate, config.Since)
+	followLogs(latestFile, logWatcher, notifyRotate, config.Since, config.Until)
 
 	l.mu.Lock()
 	delete(l.readers, logWatcher)
@@ -113,60 +95,161 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.writer.NotifyRotateEvict(notifyRotate)
 }
 
-func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since time.Time) {
-	var rdr io.Reader
-	rdr = f
-	if tail > 0 {
-		ls, err := tailfile.TailFile(f, tail)
+// logFiles are the generations of a log file: 0 is the file being written,
+// and 1 to maxFiles-1 are the rotated files, from the newest to the oldest.
+type logFiles struct {
+	path     string
+	maxFiles int
+	latest   *os.File
+}
+
+// open opens generation n. The file being written is shared with the
+// caller, so closing it is a no-op. Compressed rotated files are not
+// seekable. An error satisfying os.IsNotExist is returned if the rotated
+// file does not exist.
+func (lf *logFiles) open(n int) (io.Reader, func() error, error) {
+	if n == 0 {
+		if _, err := lf.latest.Seek(0, os.SEEK_SET); err != nil {
+			return nil, nil, err
+		}
+		return lf.latest, func() error { return nil }, nil
+	}
+	f, err := loggerutils.OpenRotatedFile(fmt.Sprintf("%s.%d", lf.path, n))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+// readFiles sends all the messages of files, oldest first, that are in the
+// time window [since, until]. The files are opened one at a time.
+func readFiles(files *logFiles, logWatcher *logger.LogWatcher, since, until time.Time) error {
+	for n := files.maxFiles - 1; n >= 0; n-- {
+		f, closeFile, err := files.open(n)
+		if os.IsNotExist(err) {
+			continue
+		}
 		if err != nil {
-			logWatcher.Err <- err
-			return
+			return err
+		}
+		more, err := sendMessages(f, logWatcher, since, until)
+		if err := closeFile(); err != nil {
+			logrus.WithField("logger", "json-file").Warnf("error closing log file: %v", err)
+		}
+		if err != nil || !more {
+			return err
 		}
-		rdr = bytes.NewBuffer(bytes.Join(ls, []byte("\n")))
 	}
-	dec := json.NewDecoder(rdr)
+	return nil
+}
+
+// sendMessages sends the messages of r that are in the time window
+// [since, until]. It returns false once a message is past until.
+func sendMessages(r io.Reader, logWatcher *logger.LogWatcher, since, until time.Time) (bool, error) {
+	dec := json.NewDecoder(r)
 	l := &jsonlog.JSONLog{}
 	for {
 		msg, err := decodeLogLine(dec, l)
+		if err == io.EOF {
+			return true, nil
+		}
 		if err != nil {
-			if err != io.EOF {
-				logWatcher.Err <- err
-			}
-			return
+			return false, err
 		}
 		if !since.IsZero() && msg.Timestamp.Before(since) {
 			continue
 		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			return false, nil
+		}
 		logWatcher.Msg <- msg
 	}
 }
 
-func watchFile(name string) (filenotify.FileWatcher, error) {
-	fileWatcher, err := filenotify.New()
-	if err != nil {
-		return nil, err
+// tailFiles sends the last tail messages of files that are in the time
+// window [since, until]. The files are opened newest first, and only as far
+// as these messages go back.
+func tailFiles(files *logFiles, logWatcher *logger.LogWatcher, tail int, since, until time.Time) error {
+	var msgs []*logger.Message // newest first
+	for n := 0; n < files.maxFiles && len(msgs) < tail; n++ {
+		f, closeFile, err := files.open(n)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fileMsgs, older, err := tailFile(f, tail-len(msgs), since, until)
+		if err := closeFile(); err != nil {
+			logrus.WithField("logger", "json-file").Warnf("error closing tailed log file: %v", err)
+		}
+		if err != nil {
+			return err
+		}
+		for i := len(fileMsgs) - 1; i >= 0; i-- {
+			msgs = append(msgs, fileMsgs[i])
+		}
+		if older {
+			break
+		}
 	}
 
-	if err := fileWatcher.Add(name); err != nil {
-		logrus.WithField("logger", "json-file").Warnf("falling back to file poller due to error: %v", err)
-		fileWatcher.Close()
-		fileWatcher = filenotify.NewPollingWatcher()
+	for i := len(msgs) - 1; i >= 0; i-- {
+		logWatcher.Msg <- msgs[i]
+	}
+	return nil
+}
 
-		if err := fileWatcher.Add(name); err != nil {
-			fileWatcher.Close()
-			logrus.Debugf("error watching log file for modifications: %v", err)
-			return nil, err
+// tailFile returns, oldest first, the last tail messages of r that are in
+// the time window [since, until]. It also returns true if r has messages
+// older than since, as the older files don't need to be read then. Only the
+// end of r is read if it can seek, otherwise it is decoded from the start.
+// The messages after until are not counted in the tail, so r is also decoded
+// from the start when until is set.
+func tailFile(r io.Reader, tail int, since, until time.Time) ([]*logger.Message, bool, error) {
+	if f, ok := r.(io.ReadSeeker); ok && until.IsZero() {
+		ls, err := tailfile.TailFile(f, tail)
+		if err != nil {
+			return nil, false, err
 		}
+		r = bytes.NewBuffer(bytes.Join(ls, []byte("\n")))
 	}
-	return fileWatcher, nil
+
+	var (
+		msgs  []*logger.Message
+		older bool
+	)
+	dec := json.NewDecoder(r)
+	l := &jsonlog.JSONLog{}
+	for {
+		msg, err := decodeLogLine(dec, l)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if !since.IsZero() && msg.Timestamp.Before(since) {
+			older = true
+			continue
+		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			break
+		}
+		msgs = append(msgs, msg)
+		if len(msgs) > tail {
+			msgs = msgs[1:]
+		}
+	}
+	return msgs, older, nil
 }
 
-func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since time.Time) {
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since, until time.Time) {
 	dec := json.NewDecoder(f)
 	l := &jsonlog.JSONLog{}
 
 	name := f.Name()
-	fileWatcher, err := watchFile(name)
+	fileWatcher, err := loggerutils.WatchFile(name)
 	if err != nil {
 		logWatcher.Err <- err
 		return
@@ -179,11 +262,22 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+
+	// Stop following once the end of the requested window is reached, even
+	// if nothing more gets written to the log.
+	var untilC <-chan time.Time
+	if !until.IsZero() {
+		t := time.NewTimer(until.Sub(time.Now()))
+		defer t.Stop()
+		untilC = t.C
+	}
 	go func() {
 		select {
 		case <-logWatcher.WatchClose():
 			fileWatcher.Remove(name)
 			cancel()
+		case <-untilC:
+			cancel()
 		case <-ctx.Done():
 			return
 		}
@@ -237,7 +331,7 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 			// Something happened, let's try and stay alive and create a new watcher
 			if retries <= 5 {
 				fileWatcher.Close()
-				fileWatcher, err = watchFile(name)
+				fileWatcher, err = loggerutils.WatchFile(name)
 				if err != nil {
 					return err
 				}
@@ -300,6 +394,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		if !since.IsZero() && msg.Timestamp.Before(since) {
 			continue
 		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			return
+		}
 		select {
 		case logWatcher.Msg <- msg:
 		case <-ctx.Done():
@@ -312,6 +409,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 				if !since.IsZero() && msg.Timestamp.Before(since) {
 					continue
 				}
+				if !until.IsZero() && msg.Timestamp.After(until) {
+					return
+				}
 				logWatcher.Msg <- msg
 			}
 		}
@@ -0,0 +1,176 @@
+// Package local provides a logger implementation that stores logs on disk in
+// a compact binary format, with rotation and optional compression.
+//
+// Each message is stored as a logdriver.LogEntry, preceded and followed by
+// its size as a big endian uint32. The trailing size allows reading a file
+// backwards, so that the last lines of a log can be found without decoding
+// the whole file.
+package local
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types/plugins/logdriver"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/go-units"
+)
+
+const (
+	// Name is the name of the driver
+	Name = "local"
+
+	encodeBinaryLen = 4
+	initialBufSize  = 2048
+	maxMsgLen       = 1000000 // 1MB
+
+	defaultMaxFileSize  int64 = 20 * 1024 * 1024
+	defaultMaxFileCount       = 5
+	defaultCompressLogs       = true
+)
+
+// LogOptKeys are the keys names used for log opts passed in to initialize the driver.
+var LogOptKeys = map[string]bool{
+	"max-file": true,
+	"max-size": true,
+	"compress": true,
+}
+
+// ValidateLogOpt looks for log driver specific options.
+func ValidateLogOpt(cfg map[string]string) error {
+	for key := range cfg {
+		if !LogOptKeys[key] {
+			return fmt.Errorf("unknown log opt '%s' for log driver %s", key, Name)
+		}
+	}
+	_, _, _, err := parseLogOpts(cfg)
+	return err
+}
+
+func init() {
+	if err := logger.RegisterLogDriver(Name, New); err != nil {
+		logrus.Fatal(err)
+	}
+	if err := logger.RegisterLogOptValidator(Name, ValidateLogOpt); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+type driver struct {
+	mu      sync.Mutex
+	closed  bool
+	writer  *loggerutils.RotateFileWriter
+	readers map[*logger.LogWatcher]struct{} // stores the active log followers
+	buf     []byte
+	entry   logdriver.LogEntry
+}
+
+// New creates a new local logger
+// You must provide the `LogPath` in the passed in context
+func New(ctx logger.Context) (logger.Logger, error) {
+	if ctx.LogPath == "" {
+		return nil, fmt.Errorf("log path is missing -- this is a bug and should not happen")
+	}
+
+	capacity, maxFiles, compress, err := parseLogOpts(ctx.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capacity, maxFiles, compress)
+	if err != nil {
+		return nil, err
+	}
+	return &driver{
+		writer:  writer,
+		readers: make(map[*logger.LogWatcher]struct{}),
+		buf:     make([]byte, initialBufSize),
+	}, nil
+}
+
+func parseLogOpts(cfg map[string]string) (capacity int64, maxFiles int, compress bool, err error) {
+	capacity = defaultMaxFileSize
+	if v, ok := cfg["max-size"]; ok {
+		capacity, err = units.FromHumanSize(v)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if capacity <= 0 {
+			return 0, 0, false, fmt.Errorf("max-size must be a positive number")
+		}
+	}
+
+	maxFiles = defaultMaxFileCount
+	if v, ok := cfg["max-file"]; ok {
+		maxFiles, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if maxFiles < 1 {
+			return 0, 0, false, fmt.Errorf("max-file cannot be less than 1")
+		}
+	}
+
+	compress = defaultCompressLogs
+	if v, ok := cfg["compress"]; ok {
+		compress, err = strconv.ParseBool(v)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if compress && maxFiles < 2 {
+			return 0, 0, false, fmt.Errorf("compress cannot be true when max-file is less than 2")
+		}
+	} else if maxFiles < 2 {
+		// there are no rotated files to compress
+		compress = false
+	}
+	return capacity, maxFiles, compress, nil
+}
+
+func (d *driver) Name() string {
+	return Name
+}
+
+// Log writes msg to the log file as a length delimited LogEntry.
+func (d *driver) Log(msg *logger.Message) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.entry.Reset()
+	d.entry.Source = msg.Source
+	d.entry.TimeNano = msg.Timestamp.UnixNano()
+	d.entry.Line = msg.Line
+	d.entry.Partial = msg.Partial
+
+	size := d.entry.Size()
+	total := size + 2*encodeBinaryLen
+	if total > len(d.buf) {
+		d.buf = make([]byte, total)
+	}
+
+	binary.BigEndian.PutUint32(d.buf, uint32(size))
+	if _, err := d.entry.MarshalTo(d.buf[encodeBinaryLen:]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(d.buf[encodeBinaryLen+size:], uint32(size))
+
+	_, err := d.writer.Write(d.buf[:total])
+	return err
+}
+
+// Close closes underlying file and signals all readers to stop.
+func (d *driver) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	err := d.writer.Close()
+	for r := range d.readers {
+		r.Close()
+		delete(d.readers, r)
+	}
+	d.mu.Unlock()
+	return err
+}
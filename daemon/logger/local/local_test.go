@@ -0,0 +1,200 @@
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+)
+
+func newTestLogger(t *testing.T, config map[string]string) (logger.Logger, string, func()) {
+	tmp, err := ioutil.TempDir("", "docker-logger-local-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(tmp, "container.log")
+	l, err := New(logger.Context{
+		ContainerID: "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657",
+		LogPath:     filename,
+		Config:      config,
+	})
+	if err != nil {
+		os.RemoveAll(tmp)
+		t.Fatal(err)
+	}
+	return l, filename, func() {
+		l.Close()
+		os.RemoveAll(tmp)
+	}
+}
+
+func readAll(t *testing.T, l logger.Logger, config logger.ReadConfig) []*logger.Message {
+	lw := l.(logger.LogReader).ReadLogs(config)
+	var msgs []*logger.Message
+	for {
+		select {
+		case msg, ok := <-lw.Msg:
+			if !ok {
+				return msgs
+			}
+			msgs = append(msgs, msg)
+		case err := <-lw.Err:
+			t.Fatal(err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout reading logs")
+		}
+	}
+}
+
+func logLines(t *testing.T, l logger.Logger, n int, start time.Time) {
+	for i := 0; i < n; i++ {
+		msg := &logger.Message{
+			Line:      []byte("line" + strconv.Itoa(i)),
+			Source:    "stdout",
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		}
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkLines(t *testing.T, msgs []*logger.Message, first, last int) {
+	if len(msgs) != last-first+1 {
+		t.Fatalf("expected %d messages, got %d", last-first+1, len(msgs))
+	}
+	for i, msg := range msgs {
+		expected := "line" + strconv.Itoa(first+i) + "\n"
+		if string(msg.Line) != expected {
+			t.Fatalf("expected %q, got %q", expected, msg.Line)
+		}
+		if msg.Source != "stdout" {
+			t.Fatalf("expected stdout, got %s", msg.Source)
+		}
+	}
+}
+
+func TestReadLogs(t *testing.T) {
+	for _, compress := range []string{"true", "false"} {
+		l, filename, cleanup := newTestLogger(t, map[string]string{"max-size": "1k", "max-file": "5", "compress": compress})
+		defer cleanup()
+
+		start := time.Now().Add(-time.Hour).Truncate(time.Second)
+		logLines(t, l, 100, start)
+
+		if compress == "true" {
+			// wait for the background compression to be done
+			l.(*driver).writer.Close()
+			if _, err := os.Stat(filename + ".1.gz"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		msgs := readAll(t, l, logger.ReadConfig{Tail: -1})
+		checkLines(t, msgs, 0, 99)
+		if !msgs[5].Timestamp.Equal(start.Add(5 * time.Second)) {
+			t.Fatalf("unexpected timestamp %v", msgs[5].Timestamp)
+		}
+
+		checkLines(t, readAll(t, l, logger.ReadConfig{Tail: 10}), 90, 99)
+		checkLines(t, readAll(t, l, logger.ReadConfig{Tail: 60}), 40, 99)
+		checkLines(t, readAll(t, l, logger.ReadConfig{Tail: -1, Since: start.Add(70 * time.Second)}), 70, 99)
+		checkLines(t, readAll(t, l, logger.ReadConfig{Tail: -1, Since: start.Add(30 * time.Second), Until: start.Add(40 * time.Second)}), 30, 40)
+		checkLines(t, readAll(t, l, logger.ReadConfig{Tail: 5, Until: start.Add(50 * time.Second)}), 46, 50)
+		checkLines(t, readAll(t, l, logger.ReadConfig{Tail: 50, Since: start.Add(95 * time.Second)}), 95, 99)
+		if msgs := readAll(t, l, logger.ReadConfig{Tail: 0}); len(msgs) != 0 {
+			t.Fatalf("expected no messages, got %d", len(msgs))
+		}
+	}
+}
+
+func TestReadLogsOnlyOpensNeededFiles(t *testing.T) {
+	l, filename, cleanup := newTestLogger(t, map[string]string{"max-size": "1k", "max-file": "5", "compress": "false"})
+	defer cleanup()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	logLines(t, l, 100, start)
+
+	// the oldest file is not read when the requested messages are newer
+	if err := ioutil.WriteFile(filename+".4", []byte("corrupted"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	checkLines(t, readAll(t, l, logger.ReadConfig{Tail: 10}), 90, 99)
+	checkLines(t, readAll(t, l, logger.ReadConfig{Tail: -1, Since: start.Add(95 * time.Second)}), 95, 99)
+}
+
+func TestPartialMessage(t *testing.T) {
+	l, _, cleanup := newTestLogger(t, nil)
+	defer cleanup()
+
+	if err := l.Log(&logger.Message{Line: []byte("partial"), Source: "stderr", Partial: true, Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	msgs := readAll(t, l, logger.ReadConfig{Tail: -1})
+	if len(msgs) != 1 || string(msgs[0].Line) != "partial" || !msgs[0].Partial || msgs[0].Source != "stderr" {
+		t.Fatalf("unexpected messages %+v", msgs)
+	}
+}
+
+func TestFollowLogs(t *testing.T) {
+	l, _, cleanup := newTestLogger(t, map[string]string{"max-size": "1k", "max-file": "2", "compress": "false"})
+	defer cleanup()
+
+	start := time.Now()
+	logLines(t, l, 5, start)
+
+	lw := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: 2, Follow: true})
+
+	var msgs []*logger.Message
+	next := func() {
+		select {
+		case msg := <-lw.Msg:
+			msgs = append(msgs, msg)
+		case err := <-lw.Err:
+			t.Fatal(err)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout following logs, got %d messages", len(msgs))
+		}
+	}
+	// the reader holds the lock until it follows the file, so the lines
+	// logged from now on can't be missed
+	next()
+	go func() {
+		// enough lines to rotate the file while following
+		for i := 5; i < 50; i++ {
+			l.Log(&logger.Message{Line: []byte("line" + strconv.Itoa(i)), Source: "stdout", Timestamp: start.Add(time.Duration(i) * time.Second)})
+		}
+	}()
+
+	for len(msgs) < 47 {
+		next()
+	}
+	lw.Close()
+	checkLines(t, msgs, 3, 49)
+}
+
+func TestValidateLogOpt(t *testing.T) {
+	for _, cfg := range []map[string]string{
+		nil,
+		{"max-size": "10m", "max-file": "3", "compress": "false"},
+		{"max-file": "1"},
+	} {
+		if err := ValidateLogOpt(cfg); err != nil {
+			t.Fatalf("unexpected error for %v: %v", cfg, err)
+		}
+	}
+	for _, cfg := range []map[string]string{
+		{"labels": "foo"},
+		{"max-size": "-1"},
+		{"max-file": "0"},
+		{"max-file": "1", "compress": "true"},
+	} {
+		if err := ValidateLogOpt(cfg); err == nil {
+			t.Fatalf("expected an error for %v", cfg)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package local
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types/plugins/logdriver"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+)
+
+// ReadLogs implements the logger's LogReader interface for the logs
+// created by this driver.
+func (d *driver) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	logWatcher := logger.NewLogWatcher()
+
+	go d.readLogs(logWatcher, config)
+	return logWatcher
+}
+
+func (d *driver) readLogs(logWatcher *logger.LogWatcher, config logger.ReadConfig) {
+	defer close(logWatcher.Msg)
+
+	// lock so that the files don't change while we read them; this blocks writes
+	d.mu.Lock()
+
+	pth := d.writer.LogPath()
+	latestFile, err := os.Open(pth)
+	if err != nil {
+		logWatcher.Err <- err
+		d.mu.Unlock()
+		return
+	}
+
+	files := &logFiles{path: pth, maxFiles: d.writer.MaxFiles(), latest: latestFile}
+	if config.Tail > 0 {
+		err = tailFiles(files, logWatcher, config)
+	} else if config.Tail < 0 {
+		err = readFiles(files, logWatcher, config)
+	}
+	if err != nil {
+		logWatcher.Err <- err
+	}
+
+	if !config.Follow || d.closed || err != nil {
+		latestFile.Close()
+		d.mu.Unlock()
+		return
+	}
+
+	// only follow what gets written from now on
+	if _, err := latestFile.Seek(0, os.SEEK_END); err != nil {
+		logWatcher.Err <- err
+		latestFile.Close()
+		d.mu.Unlock()
+		return
+	}
+
+	d.readers[logWatcher] = struct{}{}
+	// subscribe before any more writes so that no rotation is missed
+	notifyRotate := d.writer.NotifyRotate()
+	d.mu.Unlock()
+
+	followLogs(latestFile, logWatcher, notifyRotate, config)
+
+	d.mu.Lock()
+	delete(d.readers, logWatcher)
+	d.mu.Unlock()
+
+	d.writer.NotifyRotateEvict(notifyRotate)
+}
+
+// logFiles are the generations of a log file: 0 is the file being written,
+// and 1 to maxFiles-1 are the rotated files, from the newest to the oldest.
+type logFiles struct {
+	path     string
+	maxFiles int
+	latest   *os.File
+}
+
+// open opens generation n. The file being written is shared with the
+// caller, so closing it is a no-op. Compressed rotated files are not
+// seekable. An error satisfying os.IsNotExist is returned if the rotated
+// file does not exist.
+func (lf *logFiles) open(n int) (io.Reader, func() error, error) {
+	if n == 0 {
+		if _, err := lf.latest.Seek(0, os.SEEK_SET); err != nil {
+			return nil, nil, err
+		}
+		return lf.latest, func() error { return nil }, nil
+	}
+	f, err := loggerutils.OpenRotatedFile(fmt.Sprintf("%s.%d", lf.path, n))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+// readFiles sends all the messages of files, oldest first, that are in the
+// time window of config. The files are opened one at a time, and the ones
+// that only hold messages older than config.Since are not read.
+func readFiles(files *logFiles, logWatcher *logger.LogWatcher, config logger.ReadConfig) error {
+	dec := newDecoder()
+
+	// Look for the newest file that starts before config.Since, only its
+	// first message is decoded.
+	first := files.maxFiles - 1
+	if !config.Since.IsZero() {
+		for n := 0; n < files.maxFiles; n++ {
+			f, closeFile, err := files.open(n)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			err = dec.decode(f)
+			closeFile()
+			if err == io.EOF {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if time.Unix(0, dec.entry.TimeNano).Before(config.Since) {
+				first = n
+				break
+			}
+		}
+	}
+
+	for n := first; n >= 0; n-- {
+		f, closeFile, err := files.open(n)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		more, err := sendMessages(f, dec, logWatcher, config)
+		closeFile()
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendMessages sends the messages of f that are in the time window of
+// config. It returns false once a message is past config.Until.
+func sendMessages(f io.Reader, dec *decoder, logWatcher *logger.LogWatcher, config logger.ReadConfig) (bool, error) {
+	for {
+		err := dec.decode(f)
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		ts := time.Unix(0, dec.entry.TimeNano)
+		if !config.Since.IsZero() && ts.Before(config.Since) {
+			continue
+		}
+		if !config.Until.IsZero() && ts.After(config.Until) {
+			return false, nil
+		}
+		logWatcher.Msg <- dec.message()
+	}
+}
+
+// tailFiles sends the last config.Tail messages of files that are in the
+// time window of config. The files are opened newest first, and only as far
+// as these messages go back. Each of them is read backwards from the end,
+// so only the messages that are needed get decoded.
+func tailFiles(files *logFiles, logWatcher *logger.LogWatcher, config logger.ReadConfig) error {
+	dec := newDecoder()
+	var msgs []*logger.Message
+
+	for n := 0; n < files.maxFiles && len(msgs) < config.Tail; n++ {
+		f, closeFile, err := files.open(n)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var older bool
+		msgs, older, err = tailFile(f, dec, msgs, config)
+		closeFile()
+		if err != nil {
+			return err
+		}
+		if older {
+			break
+		}
+	}
+
+	for i := len(msgs) - 1; i >= 0; i-- {
+		logWatcher.Msg <- msgs[i]
+	}
+	return nil
+}
+
+// tailFile appends to msgs, newest first, the messages of f that are in the
+// time window of config, until there are config.Tail of them. It returns
+// true once a message older than config.Since is found, as the older files
+// don't need to be read then.
+func tailFile(r io.Reader, dec *decoder, msgs []*logger.Message, config logger.ReadConfig) ([]*logger.Message, bool, error) {
+	f, ok := r.(io.ReadSeeker)
+	if !ok {
+		return tailStream(r, dec, msgs, config)
+	}
+	end, err := f.Seek(0, os.SEEK_END)
+	if err != nil {
+		return msgs, false, err
+	}
+	for end > 0 && len(msgs) < config.Tail {
+		end, err = dec.decodeBefore(f, end)
+		if err != nil {
+			return msgs, false, err
+		}
+		ts := time.Unix(0, dec.entry.TimeNano)
+		if !config.Since.IsZero() && ts.Before(config.Since) {
+			return msgs, true, nil
+		}
+		if !config.Until.IsZero() && ts.After(config.Until) {
+			continue
+		}
+		msgs = append(msgs, dec.message())
+	}
+	return msgs, false, nil
+}
+
+// tailStream is tailFile for a file that can only be read from the start,
+// like a compressed one. The messages in the time window of config are
+// decoded in order, keeping only the last ones that are needed.
+func tailStream(r io.Reader, dec *decoder, msgs []*logger.Message, config logger.ReadConfig) ([]*logger.Message, bool, error) {
+	var (
+		last  []*logger.Message
+		older bool
+	)
+	for {
+		err := dec.decode(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return msgs, false, err
+		}
+		ts := time.Unix(0, dec.entry.TimeNano)
+		if !config.Since.IsZero() && ts.Before(config.Since) {
+			older = true
+			continue
+		}
+		if !config.Until.IsZero() && ts.After(config.Until) {
+			break
+		}
+		last = append(last, dec.message())
+		if len(last) > config.Tail-len(msgs) {
+			last = last[1:]
+		}
+	}
+	for i := len(last) - 1; i >= 0; i-- {
+		msgs = append(msgs, last[i])
+	}
+	return msgs, older, nil
+}
+
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, config logger.ReadConfig) {
+	name := f.Name()
+	fileWatcher, err := loggerutils.WatchFile(name)
+	if err != nil {
+		logWatcher.Err <- err
+		return
+	}
+	defer func() {
+		f.Close()
+		fileWatcher.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Stop following once the end of the requested window is reached, even
+	// if nothing more gets written to the log.
+	var untilC <-chan time.Time
+	if !config.Until.IsZero() {
+		t := time.NewTimer(config.Until.Sub(time.Now()))
+		defer t.Stop()
+		untilC = t.C
+	}
+	go func() {
+		select {
+		case <-logWatcher.WatchClose():
+			cancel()
+		case <-untilC:
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}()
+
+	dec := newDecoder()
+
+	// sendAvailable sends the messages that are fully written to f. It
+	// returns false once a message is past the end of the requested window.
+	sendAvailable := func(blocking bool) (bool, error) {
+		for {
+			pos, err := f.Seek(0, os.SEEK_CUR)
+			if err != nil {
+				return false, err
+			}
+			err = dec.decode(f)
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				// the message may not be fully written yet, read it again
+				// once there is more data
+				_, err = f.Seek(pos, os.SEEK_SET)
+				return true, err
+			}
+			if err != nil {
+				return false, err
+			}
+
+			ts := time.Unix(0, dec.entry.TimeNano)
+			if !config.Since.IsZero() && ts.Before(config.Since) {
+				continue
+			}
+			if !config.Until.IsZero() && ts.After(config.Until) {
+				return false, nil
+			}
+			if !blocking {
+				logWatcher.Msg <- dec.message()
+				continue
+			}
+			select {
+			case logWatcher.Msg <- dec.message():
+			case <-ctx.Done():
+				return false, nil
+			}
+		}
+	}
+
+	for {
+		more, err := sendAvailable(true)
+		if err != nil {
+			logWatcher.Err <- err
+			return
+		}
+		if !more {
+			return
+		}
+
+		select {
+		case <-fileWatcher.Events():
+		case <-notifyRotate:
+			// the rotated file is complete, send what's left of it
+			// before moving on to the new one
+			if more, err := sendAvailable(true); err != nil || !more {
+				if err != nil {
+					logWatcher.Err <- err
+				}
+				return
+			}
+			// Removing the watch may block on events that were not
+			// received yet, so start watching the new file from scratch.
+			f.Close()
+			fileWatcher.Close()
+			w, err := loggerutils.WatchFile(name)
+			if err != nil {
+				logWatcher.Err <- err
+				return
+			}
+			fileWatcher = w
+			if f, err = os.Open(name); err != nil {
+				logWatcher.Err <- err
+				return
+			}
+		case err := <-fileWatcher.Errors():
+			logrus.Debugf("logger got error watching file: %v", err)
+			logWatcher.Err <- err
+			return
+		case <-ctx.Done():
+			// send what was written before the logger got closed
+			if _, err := sendAvailable(false); err != nil {
+				logWatcher.Err <- err
+			}
+			return
+		}
+	}
+}
+
+// decoder reads the entries written by the driver.
+type decoder struct {
+	buf   []byte
+	entry logdriver.LogEntry
+}
+
+func newDecoder() *decoder {
+	return &decoder{buf: make([]byte, initialBufSize)}
+}
+
+// decode reads the entry at the current offset of r.
+func (d *decoder) decode(r io.Reader) error {
+	if _, err := io.ReadFull(r, d.buf[:encodeBinaryLen]); err != nil {
+		return err
+	}
+	size := int(binary.BigEndian.Uint32(d.buf[:encodeBinaryLen]))
+	if size > maxMsgLen {
+		return fmt.Errorf("log message is too large (%d > %d)", size, maxMsgLen)
+	}
+
+	// the entry is followed by its size again
+	total := size + encodeBinaryLen
+	if len(d.buf) < total {
+		d.buf = make([]byte, total)
+	}
+	if _, err := io.ReadFull(r, d.buf[:total]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	d.entry.Reset()
+	return d.entry.Unmarshal(d.buf[:size])
+}
+
+// decodeBefore reads the entry that ends at offset end of f, and returns the
+// offset at which it starts.
+func (d *decoder) decodeBefore(f io.ReadSeeker, end int64) (int64, error) {
+	if end < 2*encodeBinaryLen {
+		return 0, fmt.Errorf("log file is corrupted: truncated entry at offset %d", end)
+	}
+	if _, err := f.Seek(end-encodeBinaryLen, os.SEEK_SET); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(f, d.buf[:encodeBinaryLen]); err != nil {
+		return 0, err
+	}
+	size := int64(binary.BigEndian.Uint32(d.buf[:encodeBinaryLen]))
+	start := end - size - 2*encodeBinaryLen
+	if size > maxMsgLen || start < 0 {
+		return 0, fmt.Errorf("log file is corrupted: invalid entry size at offset %d", end)
+	}
+	if _, err := f.Seek(start, os.SEEK_SET); err != nil {
+		return 0, err
+	}
+	if err := d.decode(f); err != nil {
+		return 0, err
+	}
+	return start, nil
+}
+
+// message converts the last decoded entry to a logger.Message.
+func (d *decoder) message() *logger.Message {
+	line := d.entry.Line
+	if !d.entry.Partial {
+		line = append(line, '\n')
+	}
+	return &logger.Message{
+		Source:    d.entry.Source,
+		Timestamp: time.Unix(0, d.entry.TimeNano).UTC(),
+		Line:      line,
+		Partial:   d.entry.Partial,
+	}
+}
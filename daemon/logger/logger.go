@@ -18,7 +18,7 @@ import (
 )
 
 // ErrReadLogsNotSupported is returned when the logger does not support reading logs.
-var ErrReadLogsNotSupported = errors.New("configured logging reader does not support reading")
+var ErrReadLogsNotSupported = errors.New("configured logging driver does not support reading")
 
 const (
 	// TimeFormat is the time format used for timestamps sent to log readers.
@@ -89,10 +89,19 @@ type Logger interface {
 // ReadConfig is the configuration passed into ReadLogs.
 type ReadConfig struct {
 	Since  time.Time
+	Until  time.Time
 	Tail   int
 	Follow bool
 }
 
+// Capability defines the list of capabilities that a driver can implement.
+// These capabilities are not required to be a logging driver, however they
+// determine how a logging driver can be used.
+type Capability struct {
+	// Determines if a log driver can read back logs
+	ReadLogs bool
+}
+
 // LogReader is the interface for reading log messages for loggers that support reading.
 type LogReader interface {
 	// Read logs from underlying logging backend
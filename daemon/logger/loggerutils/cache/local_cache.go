@@ -0,0 +1,167 @@
+// Package cache provides a local, bounded copy of the messages sent to a
+// logging driver, so that logs can be read back with `docker logs` even when
+// the driver itself does not support reading.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/go-units"
+)
+
+const (
+	// EnabledOpt is the log option used to enable the local cache.
+	EnabledOpt = "cache-enabled"
+	// MaxSizeOpt is the log option used to set the maximum size of a cache file.
+	MaxSizeOpt = "cache-max-size"
+	// MaxFileOpt is the log option used to set the maximum number of cache files.
+	MaxFileOpt = "cache-max-file"
+
+	defaultMaxSize = "20m"
+	defaultMaxFile = "5"
+)
+
+var builtInCacheLogOpts = map[string]bool{
+	EnabledOpt: true,
+	MaxSizeOpt: true,
+	MaxFileOpt: true,
+}
+
+func init() {
+	logger.AddBuiltinLogOpts(builtInCacheLogOpts)
+	logger.RegisterExternalValidator(ValidateLogOpt)
+}
+
+// Enabled returns true if the log options enable the local cache.
+func Enabled(cfg map[string]string) bool {
+	enabled, _ := strconv.ParseBool(cfg[EnabledOpt])
+	return enabled
+}
+
+// MergeDefaultLogConfig copies the cache options set in the daemon's default
+// log options into cfg, unless cfg sets them already. Unlike the options of
+// the logging driver, these apply whatever driver the container uses.
+func MergeDefaultLogConfig(cfg, defaults map[string]string) {
+	for k := range builtInCacheLogOpts {
+		if _, ok := cfg[k]; ok {
+			continue
+		}
+		if v, ok := defaults[k]; ok {
+			cfg[k] = v
+		}
+	}
+}
+
+// ValidateLogOpt checks the cache options in cfg. Other options are ignored.
+func ValidateLogOpt(cfg map[string]string) error {
+	if v, ok := cfg[EnabledOpt]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid value for log option %s: %s", EnabledOpt, v)
+		}
+	}
+	if v, ok := cfg[MaxSizeOpt]; ok {
+		if _, err := units.FromHumanSize(v); err != nil {
+			return fmt.Errorf("invalid value for log option %s: %s", MaxSizeOpt, v)
+		}
+	}
+	if v, ok := cfg[MaxFileOpt]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value for log option %s: %s", MaxFileOpt, v)
+		}
+	}
+	return nil
+}
+
+// newCache creates the json-file logger storing the cache at ctx.LogPath.
+func newCache(ctx logger.Context) (logger.Logger, error) {
+	cfg := map[string]string{
+		"max-size": defaultMaxSize,
+		"max-file": defaultMaxFile,
+	}
+	if v, ok := ctx.Config[MaxSizeOpt]; ok {
+		cfg["max-size"] = v
+	}
+	if v, ok := ctx.Config[MaxFileOpt]; ok {
+		cfg["max-file"] = v
+	}
+
+	return jsonfilelog.New(logger.Context{
+		Config:      cfg,
+		ContainerID: ctx.ContainerID,
+		LogPath:     ctx.LogPath,
+	})
+}
+
+// WithLocalCache wraps l so that every message is also written to a local
+// cache stored at ctx.LogPath. Logs are read back from the cache.
+func WithLocalCache(l logger.Logger, ctx logger.Context) (logger.Logger, error) {
+	c, err := newCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggerWithCache{
+		l:     l,
+		cache: c,
+		id:    ctx.ContainerID,
+	}, nil
+}
+
+type loggerWithCache struct {
+	l     logger.Logger
+	cache logger.Logger
+	id    string
+}
+
+func (l *loggerWithCache) Log(msg *logger.Message) error {
+	// The cache writes the message out before returning, so it is done with
+	// it by the time the driver gets it.
+	if err := l.cache.Log(msg); err != nil {
+		logrus.WithField("container", l.id).Errorf("Error writing log message to the local cache: %v", err)
+	}
+	return l.l.Log(msg)
+}
+
+func (l *loggerWithCache) Name() string {
+	return l.l.Name()
+}
+
+func (l *loggerWithCache) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	return l.cache.(logger.LogReader).ReadLogs(config)
+}
+
+func (l *loggerWithCache) Close() error {
+	err := l.l.Close()
+	if cacheErr := l.cache.Close(); cacheErr != nil && err == nil {
+		err = cacheErr
+	}
+	return err
+}
+
+// NewReader opens the local cache stored at ctx.LogPath for reading only.
+// It is used to read the logs of a container that is not running, without
+// creating its logging driver, which may connect to a remote endpoint.
+func NewReader(ctx logger.Context) (logger.Logger, error) {
+	c, err := newCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyCache{c}, nil
+}
+
+type readOnlyCache struct {
+	logger.Logger
+}
+
+func (c *readOnlyCache) Log(msg *logger.Message) error {
+	return errors.New("the local log cache is opened for reading only")
+}
+
+func (c *readOnlyCache) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	return c.Logger.(logger.LogReader).ReadLogs(config)
+}
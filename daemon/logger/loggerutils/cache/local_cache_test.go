@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+)
+
+type fakeLogger struct {
+	lines  []string
+	closed bool
+}
+
+func (l *fakeLogger) Log(msg *logger.Message) error {
+	l.lines = append(l.lines, string(msg.Line))
+	return nil
+}
+
+func (l *fakeLogger) Name() string {
+	return "fake"
+}
+
+func (l *fakeLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestWithLocalCache(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fake := &fakeLogger{}
+	l, err := WithLocalCache(fake, logger.Context{
+		ContainerID: "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657",
+		LogPath:     filepath.Join(tmp, "container-cached.log"),
+		Config:      map[string]string{EnabledOpt: "true"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name() != "fake" {
+		t.Fatalf("expected name of the wrapped logger, got %s", l.Name())
+	}
+
+	for _, line := range []string{"line1", "line2", "line3"} {
+		if err := l.Log(&logger.Message{Line: []byte(line), Source: "stdout", Timestamp: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(fake.lines) != 3 {
+		t.Fatalf("expected 3 messages to be sent to the driver, got %d", len(fake.lines))
+	}
+
+	lr, ok := l.(logger.LogReader)
+	if !ok {
+		t.Fatal("expected logger with cache to support reading")
+	}
+	lw := lr.ReadLogs(logger.ReadConfig{Tail: -1})
+	var read []string
+	for msg := range lw.Msg {
+		read = append(read, string(msg.Line))
+	}
+	expected := []string{"line1\n", "line2\n", "line3\n"}
+	if len(read) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, read)
+	}
+	for i := range expected {
+		if read[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected, read)
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.closed {
+		t.Fatal("expected the wrapped logger to be closed")
+	}
+}
+
+func TestValidateLogOpt(t *testing.T) {
+	for _, cfg := range []map[string]string{
+		{EnabledOpt: "true", MaxSizeOpt: "10m", MaxFileOpt: "3"},
+		{EnabledOpt: "false"},
+		{"max-size": "not validated here"},
+	} {
+		if err := ValidateLogOpt(cfg); err != nil {
+			t.Fatalf("unexpected error for %v: %v", cfg, err)
+		}
+	}
+	for _, cfg := range []map[string]string{
+		{EnabledOpt: "yes please"},
+		{MaxSizeOpt: "big"},
+		{MaxFileOpt: "0"},
+	} {
+		if err := ValidateLogOpt(cfg); err == nil {
+			t.Fatalf("expected an error for %v", cfg)
+		}
+	}
+}
+
+func TestMergeDefaultLogConfig(t *testing.T) {
+	cfg := map[string]string{MaxFileOpt: "2"}
+	MergeDefaultLogConfig(cfg, map[string]string{EnabledOpt: "true", MaxFileOpt: "10", "max-size": "1m"})
+	if cfg[EnabledOpt] != "true" {
+		t.Fatalf("expected cache to be enabled from the defaults, got %v", cfg)
+	}
+	if cfg[MaxFileOpt] != "2" {
+		t.Fatalf("expected container option to take precedence, got %v", cfg)
+	}
+	if _, ok := cfg["max-size"]; ok {
+		t.Fatalf("expected driver options not to be merged, got %v", cfg)
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	ctx := logger.Context{
+		ContainerID: "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657",
+		LogPath:     filepath.Join(tmp, "container-cached.log"),
+		Config:      map[string]string{EnabledOpt: "true"},
+	}
+	w, err := WithLocalCache(&fakeLogger{}, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Log(&logger.Message{Line: []byte("line1"), Source: "stdout", Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := r.Log(&logger.Message{Line: []byte("line2"), Source: "stdout", Timestamp: time.Now()}); err == nil {
+		t.Fatal("expected an error writing to a read-only cache")
+	}
+
+	lw := r.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	var read []string
+	for msg := range lw.Msg {
+		read = append(read, string(msg.Line))
+	}
+	if len(read) != 1 || read[0] != "line1\n" {
+		t.Fatalf("expected %q, got %q", []string{"line1\n"}, read)
+	}
+}
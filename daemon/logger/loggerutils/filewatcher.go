@@ -0,0 +1,28 @@
+package loggerutils
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/filenotify"
+)
+
+// WatchFile returns a watcher for the log file at name, falling back to
+// polling the file if it can't be watched through filesystem events.
+func WatchFile(name string) (filenotify.FileWatcher, error) {
+	fileWatcher, err := filenotify.New()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fileWatcher.Add(name); err != nil {
+		logrus.Warnf("falling back to file poller due to error: %v", err)
+		fileWatcher.Close()
+		fileWatcher = filenotify.NewPollingWatcher()
+
+		if err := fileWatcher.Add(name); err != nil {
+			fileWatcher.Close()
+			logrus.Debugf("error watching log file for modifications: %v", err)
+			return nil, err
+		}
+	}
+	return fileWatcher, nil
+}
@@ -1,13 +1,19 @@
 package loggerutils
 
 import (
+	"compress/gzip"
+	"io"
 	"os"
 	"strconv"
 	"sync"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/pubsub"
 )
 
+// compressedExt is the extension of the rotated files once compressed.
+const compressedExt = ".gz"
+
 // RotateFileWriter is Logger implementation for default Docker logging.
 type RotateFileWriter struct {
 	f            *os.File // store for closing
@@ -15,11 +21,13 @@ type RotateFileWriter struct {
 	capacity     int64 //maximum size of each file
 	currentSize  int64 // current size of the latest file
 	maxFiles     int   //maximum number of files
+	compress     bool  // whether rotated files are compressed
+	compressWg   sync.WaitGroup
 	notifyRotate *pubsub.Publisher
 }
 
 //NewRotateFileWriter creates new RotateFileWriter
-func NewRotateFileWriter(logPath string, capacity int64, maxFiles int) (*RotateFileWriter, error) {
+func NewRotateFileWriter(logPath string, capacity int64, maxFiles int, compress bool) (*RotateFileWriter, error) {
 	log, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
 	if err != nil {
 		return nil, err
@@ -35,6 +43,7 @@ func NewRotateFileWriter(logPath string, capacity int64, maxFiles int) (*RotateF
 		capacity:     capacity,
 		currentSize:  size,
 		maxFiles:     maxFiles,
+		compress:     compress,
 		notifyRotate: pubsub.NewPublisher(0, 1),
 	}, nil
 }
@@ -65,9 +74,21 @@ func (w *RotateFileWriter) checkCapacityAndRotate() error {
 		if err := w.f.Close(); err != nil {
 			return err
 		}
+		// the previous generation must be compressed before it is renamed
+		w.compressWg.Wait()
 		if err := rotate(name, w.maxFiles); err != nil {
 			return err
 		}
+		if w.compress && w.maxFiles > 1 {
+			w.compressWg.Add(1)
+			go func() {
+				defer w.compressWg.Done()
+				if err := compressFile(name + ".1"); err != nil {
+					// the file is kept uncompressed, and rotated as it is
+					logrus.Errorf("Error compressing log file %s: %v", name+".1", err)
+				}
+			}()
+		}
 		file, err := os.OpenFile(name, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 06400)
 		if err != nil {
 			return err
@@ -80,17 +101,29 @@ func (w *RotateFileWriter) checkCapacityAndRotate() error {
 	return nil
 }
 
+// rotate shifts the rotated files of name by one generation, and renames
+// name to name.1. A rotated file may or may not be compressed, as the
+// compression can fail or be turned off, so both forms are shifted.
 func rotate(name string, maxFiles int) error {
 	if maxFiles < 2 {
 		return nil
 	}
-	for i := maxFiles - 1; i > 1; i-- {
-		toPath := name + "." + strconv.Itoa(i)
-		fromPath := name + "." + strconv.Itoa(i-1)
-		if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
+	exts := []string{"", compressedExt}
+	for _, ext := range exts {
+		oldest := name + "." + strconv.Itoa(maxFiles-1) + ext
+		if err := os.Remove(oldest); err != nil && !os.IsNotExist(err) {
 			return err
 		}
 	}
+	for i := maxFiles - 1; i > 1; i-- {
+		for _, ext := range exts {
+			toPath := name + "." + strconv.Itoa(i) + ext
+			fromPath := name + "." + strconv.Itoa(i-1) + ext
+			if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
 
 	if err := os.Rename(name, name+".1"); err != nil && !os.IsNotExist(err) {
 		return err
@@ -98,6 +131,88 @@ func rotate(name string, maxFiles int) error {
 	return nil
 }
 
+// compressFile gzips the file at name to name.gz and removes the original.
+// The compressed file only appears once it is complete.
+func compressFile(name string) (retErr error) {
+	src, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	tmp := name + compressedExt + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			dst.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name+compressedExt); err != nil {
+		return err
+	}
+	// The original can't be removed while it is being read on Windows. Only
+	// one of the two is kept, so that readers don't get the messages twice.
+	if err := os.Remove(name); err != nil {
+		os.Remove(name + compressedExt)
+		return err
+	}
+	return nil
+}
+
+// OpenRotatedFile opens the rotated log file at name for reading. If the
+// file has been compressed, it is decompressed as it is read, and the
+// returned reader can't seek. An error satisfying os.IsNotExist is returned
+// if neither exists.
+func OpenRotatedFile(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	zf, err := os.Open(name + compressedExt)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(zf)
+	if err != nil {
+		zf.Close()
+		return nil, err
+	}
+	return &compressedFile{Reader: zr, f: zf}, nil
+}
+
+// compressedFile decompresses a rotated log file as it is read.
+type compressedFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (c *compressedFile) Close() error {
+	c.Reader.Close()
+	return c.f.Close()
+}
+
 // LogPath returns the location the given writer logs to.
 func (w *RotateFileWriter) LogPath() string {
 	return w.f.Name()
@@ -120,5 +235,6 @@ func (w *RotateFileWriter) NotifyRotateEvict(sub chan interface{}) {
 
 // Close closes underlying file and signals all readers to stop.
 func (w *RotateFileWriter) Close() error {
+	w.compressWg.Wait()
 	return w.f.Close()
 }
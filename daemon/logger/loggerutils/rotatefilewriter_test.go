@@ -0,0 +1,86 @@
+package loggerutils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotateKeepsUncompressedFiles(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-rotate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	name := filepath.Join(tmp, "container.log")
+
+	// .1 could not be compressed, .2 was
+	for _, f := range []string{name, name + ".1", name + ".2.gz", name + ".3"} {
+		if err := ioutil.WriteFile(f, []byte(filepath.Base(f)), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rotate(name, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		name + ".1":    "container.log",
+		name + ".2":    "container.log.1",
+		name + ".3.gz": "container.log.2.gz",
+	}
+	files, err := filepath.Glob(name + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("expected files %v, got %v", expected, files)
+	}
+	for f, content := range expected {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Fatalf("expected %s to hold %q, got %q", f, content, b)
+		}
+	}
+}
+
+func TestOpenRotatedFile(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-rotate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	name := filepath.Join(tmp, "container.log.1")
+
+	if _, err := OpenRotatedFile(name); !os.IsNotExist(err) {
+		t.Fatalf("expected a not exist error, got %v", err)
+	}
+
+	if err := ioutil.WriteFile(name, []byte("content"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressFile(name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed once compressed, got %v", name, err)
+	}
+
+	f, err := OpenRotatedFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "content" {
+		t.Fatalf("expected %q, got %q", "content", b)
+	}
+}
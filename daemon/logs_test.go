@@ -1,9 +1,16 @@
 package daemon
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
 	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/logger"
+	logcache "github.com/docker/docker/daemon/logger/loggerutils/cache"
 )
 
 func TestMergeAndVerifyLogConfigNilConfig(t *testing.T) {
@@ -13,3 +20,89 @@ func TestMergeAndVerifyLogConfigNilConfig(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestMergeAndVerifyLogConfigCacheOpts(t *testing.T) {
+	d := &Daemon{defaultLogConfig: containertypes.LogConfig{Type: "json-file", Config: map[string]string{"cache-enabled": "true", "max-file": "1"}}}
+	cfg := containertypes.LogConfig{Type: "syslog", Config: map[string]string{"syslog-facility": "daemon"}}
+	if err := d.mergeAndVerifyLogConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Config["cache-enabled"] != "true" {
+		t.Fatalf("expected cache option to be merged for every driver, got %v", cfg.Config)
+	}
+	if _, ok := cfg.Config["max-file"]; ok {
+		t.Fatalf("expected json-file option not to be merged for syslog, got %v", cfg.Config)
+	}
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Log(*logger.Message) error { return nil }
+func (discardLogger) Name() string              { return "discard" }
+func (discardLogger) Close() error              { return nil }
+
+func TestGetLoggerReadsCacheOfStoppedContainer(t *testing.T) {
+	const driverName = "test-unreachable"
+	if err := logger.RegisterLogDriver(driverName, func(logger.Context) (logger.Logger, error) {
+		return nil, errors.New("endpoint is unreachable")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := ioutil.TempDir("", "docker-logs-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	c := container.NewBaseContainer("a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657", root)
+	c.Config = &containertypes.Config{}
+	c.HostConfig = &containertypes.HostConfig{
+		LogConfig: containertypes.LogConfig{
+			Type:   driverName,
+			Config: map[string]string{logcache.EnabledOpt: "true"},
+		},
+	}
+
+	d := &Daemon{}
+	// Without a cache, the driver has to be created to read the logs
+	if _, err := d.getLogger(c); err == nil {
+		t.Fatal("expected an error creating the logging driver")
+	}
+
+	logPath, err := c.GetRootResourcePath("container-cached.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := logcache.WithLocalCache(discardLogger{}, logger.Context{
+		ContainerID: c.ID,
+		LogPath:     logPath,
+		Config:      c.HostConfig.LogConfig.Config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Log(&logger.Message{Line: []byte("cached"), Source: "stdout", Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	l, err := d.getLogger(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	lw := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	defer lw.Close()
+	select {
+	case msg, ok := <-lw.Msg:
+		if !ok || string(msg.Line) != "cached\n" {
+			t.Fatalf("expected the cached message, got %v", msg)
+		}
+	case err := <-lw.Err:
+		t.Fatal(err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the cached message")
+	}
+}
@@ -0,0 +1,113 @@
+package distribution
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+)
+
+// PartialDownloadStore keeps the data of the layer downloads that were
+// interrupted on disk, keyed by the digest of the layer, so that a later pull
+// of the layer can resume the download instead of starting over.
+type PartialDownloadStore struct {
+	root    string
+	timeout time.Duration
+
+	mu    sync.Mutex
+	inUse map[digest.Digest]struct{}
+}
+
+// NewPartialDownloadStore returns a PartialDownloadStore that keeps the
+// partial downloads under root, and removes the ones that were not written
+// to for longer than timeout.
+func NewPartialDownloadStore(root string, timeout time.Duration) (*PartialDownloadStore, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	s := &PartialDownloadStore{
+		root:    root,
+		timeout: timeout,
+		inUse:   make(map[digest.Digest]struct{}),
+	}
+	s.GC()
+	return s, nil
+}
+
+func (s *PartialDownloadStore) path(dgst digest.Digest) string {
+	return filepath.Join(s.root, string(dgst.Algorithm()), dgst.Hex())
+}
+
+// open opens the partial download of the layer dgst for writing, creating
+// it if there is none. The data of the layer is appended to the file. It
+// returns nil if the layer is already being downloaded.
+func (s *PartialDownloadStore) open(dgst digest.Digest) (*os.File, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inUse[dgst]; ok {
+		return nil, nil
+	}
+	p := s.path(dgst)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(p, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.inUse[dgst] = struct{}{}
+	return f, nil
+}
+
+// release closes the partial download f of the layer dgst. The file is kept
+// for a later download if it has data and keep is true, and removed
+// otherwise.
+func (s *PartialDownloadStore) release(dgst digest.Digest, f *os.File, keep bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keep {
+		if fi, err := f.Stat(); err != nil || fi.Size() == 0 {
+			keep = false
+		}
+	}
+	f.Close()
+	if !keep {
+		if err := os.Remove(f.Name()); err != nil && !os.IsNotExist(err) {
+			logrus.Errorf("Failed to remove partial download %s: %v", f.Name(), err)
+		}
+	}
+	delete(s.inUse, dgst)
+}
+
+// GC removes the partial downloads that are not in use, and were not
+// written to for longer than the timeout of the store.
+func (s *PartialDownloadStore) GC() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filepath.Walk(s.root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return nil
+		}
+		dgst := digest.NewDigestFromHex(filepath.Base(filepath.Dir(path)), fi.Name())
+		if _, ok := s.inUse[dgst]; ok {
+			return nil
+		}
+		if time.Since(fi.ModTime()) > s.timeout {
+			logrus.Debugf("removing expired partial download %s", path)
+			if err := os.Remove(path); err != nil {
+				logrus.Errorf("Failed to remove partial download %s: %v", path, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,159 @@
+package distribution
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/digest"
+	distreference "github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/pkg/progress"
+	"golang.org/x/net/context"
+)
+
+func TestPartialDownloadStore(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-download-store-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	s, err := NewPartialDownloadStore(root, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dgst := digest.FromBytes([]byte("layer"))
+
+	f, err := s.open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("lay")); err != nil {
+		t.Fatal(err)
+	}
+	if f2, err := s.open(dgst); err != nil || f2 != nil {
+		t.Fatalf("expected a layer in use not to be opened again, got %v, %v", f2, err)
+	}
+	s.release(dgst, f, true)
+
+	// The data is kept for the next download, and not collected before the
+	// timeout.
+	s.GC()
+	f, err = s.open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "lay" {
+		t.Fatalf("expected partial data %q, got %q", "lay", data)
+	}
+
+	// Partial downloads in use are not collected.
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(f.Name(), old, old); err != nil {
+		t.Fatal(err)
+	}
+	s.GC()
+	if _, err := os.Stat(f.Name()); err != nil {
+		t.Fatalf("expected partial download in use to be kept: %v", err)
+	}
+	s.release(dgst, f, true)
+	if err := os.Chtimes(f.Name(), old, old); err != nil {
+		t.Fatal(err)
+	}
+	s.GC()
+	if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
+		t.Fatalf("expected expired partial download to be removed, got %v", err)
+	}
+
+	// Partial downloads without data are not kept.
+	f, err = s.open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.release(dgst, f, true)
+	if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
+		t.Fatalf("expected empty partial download to be removed, got %v", err)
+	}
+}
+
+func TestV2LayerDescriptorResumeDownload(t *testing.T) {
+	root, err := ioutil.TempDir("", "partial-download-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	blob := bytes.Repeat([]byte("layer data "), 1000)
+	dgst := digest.FromBytes(blob)
+
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/blobs/"+dgst.String()) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("Docker-Content-Digest", dgst.String())
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blob))
+	}))
+	defer ts.Close()
+
+	named, err := distreference.ParseNamed("foo/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := client.NewRepository(context.Background(), named, ts.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewPartialDownloadStore(root, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The data of an earlier, interrupted pull.
+	f, err := s.open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(blob[:4000]); err != nil {
+		t.Fatal(err)
+	}
+	s.release(dgst, f, true)
+
+	ld := &v2LayerDescriptor{
+		digest:       dgst,
+		repo:         repo,
+		partialStore: s,
+	}
+	rc, _, err := ld.Download(context.Background(), progress.ChanOutput(make(chan progress.Progress, 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, blob) {
+		t.Fatal("downloaded data does not match the blob")
+	}
+	rc.Close()
+	ld.Close()
+
+	if len(ranges) == 0 || ranges[len(ranges)-1] != "bytes=4000-" {
+		t.Fatalf("expected the download to resume at byte 4000, got ranges %q", ranges)
+	}
+	if _, err := os.Stat(s.path(dgst)); !os.IsNotExist(err) {
+		t.Fatalf("expected the partial download to be removed, got %v", err)
+	}
+}
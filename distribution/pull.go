@@ -40,6 +40,9 @@ type ImagePullConfig struct {
 	ReferenceStore reference.Store
 	// DownloadManager manages concurrent pulls.
 	DownloadManager *xfer.LayerDownloadManager
+	// PartialDownloadStore keeps the data of interrupted layer downloads,
+	// so that they can be resumed. If nil, the data is thrown away.
+	PartialDownloadStore *PartialDownloadStore
 }
 
 // Puller is an interface that abstracts pulling for different API versions.
@@ -94,6 +97,10 @@ func Pull(ctx context.Context, ref reference.Named, imagePullConfig *ImagePullCo
 		return err
 	}
 
+	if imagePullConfig.PartialDownloadStore != nil {
+		imagePullConfig.PartialDownloadStore.GC()
+	}
+
 	var (
 		lastErr error
 
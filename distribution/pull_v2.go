@@ -135,7 +135,9 @@ type v2LayerDescriptor struct {
 	repoInfo          *registry.RepositoryInfo
 	repo              distribution.Repository
 	V2MetadataService metadata.V2MetadataService
+	partialStore      *PartialDownloadStore
 	tmpFile           *os.File
+	partial           bool // tmpFile comes from partialStore
 	verifier          digest.Verifier
 	src               distribution.Descriptor
 }
@@ -160,28 +162,24 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		offset int64
 	)
 
+	// The download file may already have data, from an earlier attempt of
+	// this pull, or from an earlier pull that was interrupted.
 	if ld.tmpFile == nil {
-		ld.tmpFile, err = createDownloadFile()
-		if err != nil {
+		if err := ld.openDownloadFile(); err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
-	} else {
-		offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
-		if err != nil {
-			logrus.Debugf("error seeking to end of download file: %v", err)
-			offset = 0
+	}
+	offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
+	if err != nil {
+		logrus.Debugf("error seeking to end of download file: %v", err)
+		offset = 0
 
-			ld.tmpFile.Close()
-			if err := os.Remove(ld.tmpFile.Name()); err != nil {
-				logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
-			}
-			ld.tmpFile, err = createDownloadFile()
-			if err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
-		} else if offset != 0 {
-			logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
+		ld.closeDownloadFile(false)
+		if err := ld.openDownloadFile(); err != nil {
+			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
+	} else if offset != 0 {
+		logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
 	}
 
 	tmpFile := ld.tmpFile
@@ -233,6 +231,16 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		if err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
+		// The data of an earlier pull has to go through the verifier
+		// before the rest of the layer.
+		if offset != 0 {
+			if _, err := io.Copy(ld.verifier, io.NewSectionReader(tmpFile, 0, offset)); err != nil {
+				if err := ld.truncateDownloadFile(); err != nil {
+					return nil, 0, xfer.DoNotRetry{Err: err}
+				}
+				return nil, 0, err
+			}
+		}
 	}
 
 	_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
@@ -261,6 +269,8 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 			return nil, 0, err
 		}
+		// Do not keep the data for a later pull
+		ld.truncateDownloadFile()
 		return nil, 0, xfer.DoNotRetry{Err: err}
 	}
 
@@ -270,11 +280,7 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 	_, err = tmpFile.Seek(0, os.SEEK_SET)
 	if err != nil {
-		tmpFile.Close()
-		if err := os.Remove(tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
-		ld.tmpFile = nil
+		ld.closeDownloadFile(false)
 		ld.verifier = nil
 		return nil, 0, xfer.DoNotRetry{Err: err}
 	}
@@ -282,8 +288,13 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	// hand off the temporary file to the download manager, so it will only
 	// be closed once
 	ld.tmpFile = nil
+	partial := ld.partial
 
 	return ioutils.NewReadCloserWrapper(tmpFile, func() error {
+		if partial {
+			ld.partialStore.release(ld.digest, tmpFile, false)
+			return nil
+		}
 		tmpFile.Close()
 		err := os.RemoveAll(tmpFile.Name())
 		if err != nil {
@@ -293,13 +304,49 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	}), size, nil
 }
 
+// Close closes the download file. If the download did not complete, its
+// data is kept in the partial download store, if there is one.
 func (ld *v2LayerDescriptor) Close() {
 	if ld.tmpFile != nil {
-		ld.tmpFile.Close()
-		if err := os.RemoveAll(ld.tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
+		ld.closeDownloadFile(true)
+	}
+}
+
+// openDownloadFile opens the partial download of the layer, or creates a
+// temporary download file if there is no partial download store or the
+// layer is already being downloaded.
+func (ld *v2LayerDescriptor) openDownloadFile() error {
+	if ld.partialStore != nil {
+		f, err := ld.partialStore.open(ld.digest)
+		if err != nil {
+			logrus.Warnf("Failed to open partial download of %s: %v", ld.digest, err)
+		} else if f != nil {
+			ld.tmpFile = f
+			ld.partial = true
+			return nil
 		}
 	}
+
+	var err error
+	ld.tmpFile, err = createDownloadFile()
+	ld.partial = false
+	return err
+}
+
+// closeDownloadFile closes the download file. The data of a partial
+// download is kept if keep is true, all other download files are removed.
+func (ld *v2LayerDescriptor) closeDownloadFile(keep bool) {
+	tmpFile := ld.tmpFile
+	ld.tmpFile = nil
+
+	if ld.partial {
+		ld.partialStore.release(ld.digest, tmpFile, keep)
+		return
+	}
+	tmpFile.Close()
+	if err := os.RemoveAll(tmpFile.Name()); err != nil {
+		logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
+	}
 }
 
 func (ld *v2LayerDescriptor) truncateDownloadFile() error {
@@ -465,6 +512,7 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 			repoInfo:          p.repoInfo,
 			repo:              p.repo,
 			V2MetadataService: p.V2MetadataService,
+			partialStore:      p.config.PartialDownloadStore,
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -515,6 +563,7 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 			repoInfo:          p.repoInfo,
 			V2MetadataService: p.V2MetadataService,
 			src:               d,
+			partialStore:      p.config.PartialDownloadStore,
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -130,7 +130,10 @@ type History struct {
 type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
-	Save([]string, io.Writer) error
+	// Save writes the images to the writer, in the archive format passed
+	// as second argument, leaving out the layers of the images passed as
+	// third argument.
+	Save([]string, string, []string, io.Writer) error
 }
 
 // NewFromJSON creates an Image configuration from json.
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/distribution"
@@ -44,7 +45,8 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	if err := chrootarchive.Untar(inTar, tmpDir, nil); err != nil {
 		return err
 	}
-	// read manifest, if no file then load in legacy mode
+	// read manifest, if no file then load as an OCI image layout, or in
+	// legacy mode
 	manifestPath, err := safePath(tmpDir, manifestFileName)
 	if err != nil {
 		return err
@@ -52,6 +54,13 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	manifestFile, err := os.Open(manifestPath)
 	if err != nil {
 		if os.IsNotExist(err) {
+			layoutPath, err := safePath(tmpDir, ociLayoutFileName)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(layoutPath); err == nil {
+				return l.ociLoad(tmpDir, outStream, progressOutput)
+			}
 			return l.legacyLoad(tmpDir, outStream, progressOutput)
 		}
 		return err
@@ -97,6 +106,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 			r.Append(diffID)
 			newLayer, err := l.ls.Get(r.ChainID())
 			if err != nil {
+				if _, err := os.Lstat(layerPath); os.IsNotExist(err) {
+					return fmt.Errorf("layer %s is not in the archive, and was not found locally", diffID)
+				}
 				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), m.LayerSources[diffID], progressOutput)
 				if err != nil {
 					return err
@@ -210,6 +222,167 @@ func (l *tarexporter) setLoadedTag(ref reference.NamedTagged, imgID digest.Diges
 	return nil
 }
 
+// ociLoad loads the images of an OCI image layout. The images are tagged
+// with the references of their manifests in the index of the layout, when
+// these are valid image references.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ociLayout
+	if err := readJSONFile(tmpDir, ociLayoutFileName, &layout); err != nil {
+		return err
+	}
+	if layout.Version != ociImageLayoutVersion {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.Version)
+	}
+
+	var index ociIndex
+	if err := readJSONFile(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	var imageIDsStr string
+	var imageRefCount int
+
+	for _, desc := range index.Manifests {
+		if desc.MediaType != mediaTypeOCIManifest {
+			return fmt.Errorf("unsupported media type %q in OCI image index", desc.MediaType)
+		}
+		imgID, err := l.ociLoadImage(tmpDir, desc, progressOutput)
+		if err != nil {
+			return err
+		}
+		imageIDsStr += fmt.Sprintf("Loaded image ID: %s\n", imgID)
+
+		if refName := desc.Annotations[ociRefNameAnnotation]; refName != "" {
+			ref, err := ociReference(refName)
+			if err != nil {
+				logrus.Warnf("Not tagging image %s with OCI reference %q: %v", imgID, refName, err)
+				fmt.Fprintf(outStream, "Not tagging image %s with OCI reference %q: %v\n", imgID, refName, err)
+			} else {
+				l.setLoadedTag(ref, imgID.Digest(), outStream)
+				outStream.Write([]byte(fmt.Sprintf("Loaded image: %s\n", ref)))
+				imageRefCount++
+			}
+		}
+
+		l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+	}
+
+	if imageRefCount == 0 {
+		outStream.Write([]byte(imageIDsStr))
+	}
+
+	return nil
+}
+
+// ociReference returns the reference to tag an image with from the
+// reference name of its manifest in an OCI image layout. Other tools often
+// only store a tag, like "latest", which can't be told apart from a
+// repository name without a domain, and can't be used without a repository.
+func ociReference(refName string) (reference.NamedTagged, error) {
+	named, err := reference.ParseNamed(refName)
+	if err != nil {
+		return nil, err
+	}
+	if ref, ok := named.(reference.NamedTagged); ok {
+		return ref, nil
+	}
+	if !reference.IsNameOnly(named) {
+		return nil, fmt.Errorf("digest references are not supported")
+	}
+	if !strings.Contains(refName, "/") {
+		return nil, fmt.Errorf("the reference has no repository name, it is only a tag: use docker tag to name the image")
+	}
+	return reference.WithDefaultTag(named).(reference.NamedTagged), nil
+}
+
+func (l *tarexporter) ociLoadImage(tmpDir string, desc ociDescriptor, progressOutput progress.Output) (image.ID, error) {
+	manifestJSON, err := readOCIBlob(tmpDir, desc)
+	if err != nil {
+		return "", err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return "", err
+	}
+
+	config, err := readOCIBlob(tmpDir, manifest.Config)
+	if err != nil {
+		return "", err
+	}
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	if img.RootFS == nil {
+		return "", fmt.Errorf("invalid image config %s: missing rootfs", manifest.Config.Digest)
+	}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+
+	if expected, actual := len(manifest.Layers), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	for i, diffID := range img.RootFS.DiffIDs {
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.ls.Get(r.ChainID())
+		if err != nil {
+			if err := manifest.Layers[i].Digest.Validate(); err != nil {
+				return "", err
+			}
+			layerPath, err := safePath(tmpDir, ociBlobPath(manifest.Layers[i].Digest))
+			if err != nil {
+				return "", err
+			}
+			newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), distribution.Descriptor{}, progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.ls, newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
+// readOCIBlob reads the blob of desc from the OCI image layout in dir, and
+// verifies its digest.
+func readOCIBlob(dir string, desc ociDescriptor) ([]byte, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	blobPath, err := safePath(dir, ociBlobPath(desc.Digest))
+	if err != nil {
+		return nil, err
+	}
+	blob, err := ioutil.ReadFile(blobPath)
+	if err != nil {
+		return nil, err
+	}
+	if actual := desc.Digest.Algorithm().FromBytes(blob); actual != desc.Digest {
+		return nil, fmt.Errorf("invalid digest for blob %s: got %s", desc.Digest, actual)
+	}
+	return blob, nil
+}
+
+func readJSONFile(dir, name string, v interface{}) error {
+	path, err := safePath(dir, name)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
+}
+
 func (l *tarexporter) legacyLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
 	legacyLoadedMap := make(map[string]image.ID)
 
@@ -0,0 +1,29 @@
+package tarexport
+
+import "testing"
+
+func TestOCIReference(t *testing.T) {
+	valid := map[string]string{
+		"busybox:latest":             "busybox:latest",
+		"example.com/foo/bar:1.0":    "example.com/foo/bar:1.0",
+		"example.com/foo/bar":        "example.com/foo/bar:latest",
+		"library/busybox":            "busybox:latest",
+		"localhost:5000/busybox:1.0": "localhost:5000/busybox:1.0",
+	}
+	for refName, expected := range valid {
+		ref, err := ociReference(refName)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", refName, err)
+		}
+		if ref.String() != expected {
+			t.Fatalf("expected %q for %q, got %q", expected, refName, ref.String())
+		}
+	}
+
+	// tags without a repository can't be used to tag the image
+	for _, refName := range []string{"latest", "1.0", "Invalid:Ref"} {
+		if _, err := ociReference(refName); err == nil {
+			t.Fatalf("expected an error for %q", refName)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package tarexport
+
+import (
+	"github.com/docker/distribution/digest"
+)
+
+// The files and media types of the OCI image layout, as defined in
+// https://github.com/opencontainers/image-spec/blob/master/image-layout.md
+const (
+	ociLayoutFileName     = "oci-layout"
+	ociIndexFileName      = "index.json"
+	ociBlobsDirName       = "blobs"
+	ociImageLayoutVersion = "1.0.0"
+
+	mediaTypeOCIIndex    = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIConfig   = "application/vnd.oci.image.config.v1+json"
+	mediaTypeOCILayer    = "application/vnd.oci.image.layer.v1.tar"
+
+	// ociRefNameAnnotation is the annotation of a manifest in the index that
+	// holds the reference of the image.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+type ociLayout struct {
+	Version string `json:"imageLayoutVersion"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// ociBlobPath returns the path of the blob with digest d, relative to the
+// root of the layout.
+func ociBlobPath(d digest.Digest) string {
+	return ociBlobsDirName + "/" + d.Algorithm().String() + "/" + d.Hex()
+}
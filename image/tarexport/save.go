@@ -1,6 +1,7 @@
 package tarexport
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/image/v1"
 	"github.com/docker/docker/layer"
@@ -27,19 +29,37 @@ type imageDescriptor struct {
 
 type saveSession struct {
 	*tarexporter
-	outDir      string
-	images      map[image.ID]*imageDescriptor
-	savedLayers map[string]struct{}
-	diffIDPaths map[layer.DiffID]string // cache every diffID blob to avoid duplicates
+	outDir         string
+	images         map[image.ID]*imageDescriptor
+	savedLayers    map[string]struct{}
+	diffIDPaths    map[layer.DiffID]string // cache every diffID blob to avoid duplicates
+	ociLayers      map[layer.DiffID]ociDescriptor
+	excludedLayers map[layer.ChainID]struct{}
 }
 
-func (l *tarexporter) Save(names []string, outStream io.Writer) error {
+func (l *tarexporter) Save(names []string, format string, excludeLayersFrom []string, outStream io.Writer) error {
 	images, err := l.parseNames(names)
 	if err != nil {
 		return err
 	}
 
-	return (&saveSession{tarexporter: l, images: images}).save(outStream)
+	s := &saveSession{tarexporter: l, images: images}
+	if len(excludeLayersFrom) > 0 {
+		if format == types.ImageSaveFormatOCI {
+			return fmt.Errorf("layers cannot be excluded from an OCI image layout")
+		}
+		if s.excludedLayers, err = l.layerChainIDs(excludeLayersFrom); err != nil {
+			return err
+		}
+	}
+
+	switch format {
+	case "", types.ImageSaveFormatDocker:
+		return s.save(outStream)
+	case types.ImageSaveFormatOCI:
+		return s.saveOCI(outStream)
+	}
+	return fmt.Errorf("invalid format %q", format)
 }
 
 func (l *tarexporter) parseNames(names []string) (map[image.ID]*imageDescriptor, error) {
@@ -117,6 +137,29 @@ func (l *tarexporter) parseNames(names []string) (map[image.ID]*imageDescriptor,
 	return imgDescr, nil
 }
 
+// layerChainIDs returns the chain IDs of all the layers of the images names.
+func (l *tarexporter) layerChainIDs(names []string) (map[layer.ChainID]struct{}, error) {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	chainIDs := make(map[layer.ChainID]struct{})
+	for id := range images {
+		img, err := l.is.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		rootFS := *img.RootFS
+		rootFS.DiffIDs = nil
+		for _, diffID := range img.RootFS.DiffIDs {
+			rootFS.Append(diffID)
+			chainIDs[rootFS.ChainID()] = struct{}{}
+		}
+	}
+	return chainIDs, nil
+}
+
 func (s *saveSession) save(outStream io.Writer) error {
 	s.savedLayers = make(map[string]struct{})
 	s.diffIDPaths = make(map[layer.DiffID]string)
@@ -303,6 +346,18 @@ func (s *saveSession) saveLayer(id layer.ChainID, legacyImg image.V1Image, creat
 		return distribution.Descriptor{}, err
 	}
 
+	// An excluded layer has no layer.tar, Load takes it from the layer store
+	// of the daemon instead.
+	if _, excluded := s.excludedLayers[id]; excluded {
+		for _, fname := range []string{"", legacyVersionFileName, legacyConfigFileName} {
+			if err := system.Chtimes(filepath.Join(outDir, fname), createdTime, createdTime); err != nil {
+				return distribution.Descriptor{}, err
+			}
+		}
+		s.savedLayers[legacyImg.ID] = struct{}{}
+		return distribution.Descriptor{}, nil
+	}
+
 	// serialize filesystem
 	layerPath := filepath.Join(outDir, legacyLayerFileName)
 	l, err := s.ls.Get(id)
@@ -356,3 +411,165 @@ func (s *saveSession) saveLayer(id layer.ChainID, legacyImg image.V1Image, creat
 	}
 	return src, nil
 }
+
+// saveOCI writes the images as an OCI image layout. Every reference of an
+// image gets its own entry in the index of the layout.
+func (s *saveSession) saveOCI(outStream io.Writer) error {
+	s.ociLayers = make(map[layer.DiffID]ociDescriptor)
+
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s.outDir = tempDir
+	index := ociIndex{SchemaVersion: 2}
+
+	for id, imageDescr := range s.images {
+		desc, err := s.saveOCIImage(id)
+		if err != nil {
+			return err
+		}
+
+		if len(imageDescr.refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, ref := range imageDescr.refs {
+			refDesc := desc
+			refDesc.Annotations = map[string]string{ociRefNameAnnotation: ref.String()}
+			index.Manifests = append(index.Manifests, refDesc)
+		}
+
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	if err := writeJSONFile(filepath.Join(tempDir, ociLayoutFileName), ociLayout{Version: ociImageLayoutVersion}); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(tempDir, ociIndexFileName), index); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveOCIImage writes the config, the layers and the manifest of an image
+// as blobs, and returns the descriptor of the manifest.
+func (s *saveSession) saveOCIImage(id image.ID) (ociDescriptor, error) {
+	img, err := s.is.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ociDescriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	manifest := ociManifest{SchemaVersion: 2}
+	manifest.Config, err = s.writeOCIBlob(mediaTypeOCIConfig, bytes.NewReader(img.RawJSON()))
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		desc, err := s.saveOCILayer(rootFS.ChainID())
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	return s.writeOCIBlob(mediaTypeOCIManifest, bytes.NewReader(manifestJSON))
+}
+
+func (s *saveSession) saveOCILayer(id layer.ChainID) (ociDescriptor, error) {
+	l, err := s.ls.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.ls, l)
+
+	if desc, exists := s.ociLayers[l.DiffID()]; exists {
+		return desc, nil
+	}
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer arch.Close()
+
+	desc, err := s.writeOCIBlob(mediaTypeOCILayer, arch)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	s.ociLayers[l.DiffID()] = desc
+	return desc, nil
+}
+
+// writeOCIBlob writes the content of r to the blobs of the layout, and
+// returns its descriptor.
+func (s *saveSession) writeOCIBlob(mediaType string, r io.Reader) (ociDescriptor, error) {
+	// Use system.CreateSequential rather than os.Create. This ensures sequential
+	// file access on Windows to avoid eating into MM standby list.
+	tmpPath := filepath.Join(s.outDir, "blob.tmp")
+	f, err := system.CreateSequential(tmpPath)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	digester := digest.Canonical.New()
+	size, err := io.Copy(io.MultiWriter(f, digester.Hash()), r)
+	f.Close()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	desc := ociDescriptor{
+		MediaType: mediaType,
+		Digest:    digester.Digest(),
+		Size:      size,
+	}
+	blobPath := filepath.Join(s.outDir, filepath.FromSlash(ociBlobPath(desc.Digest)))
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := os.Rename(tmpPath, blobPath); err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ociDescriptor{}, err
+	}
+	return desc, nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(f).Encode(v); err != nil {
+		f.Close()
+		return err
+	}
+
+	f.Close()
+
+	return system.Chtimes(path, time.Unix(0, 0), time.Unix(0, 0))
+}
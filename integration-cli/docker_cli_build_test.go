@@ -7357,3 +7357,128 @@ func (s *DockerSuite) TestBuildWindowsEnvCaseInsensitive(c *check.C) {
 		c.Fatalf("Case insensitive environment variables on Windows failed. Got %s", res)
 	}
 }
+
+func (s *DockerSuite) TestBuildCopyFromPreviousRootFS(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerfile := `
+		FROM busybox AS first
+		COPY foo bar
+		FROM busybox
+		COPY --from=0 bar baz
+		COPY --from=first bar bay
+		FROM busybox
+		COPY --from=1 baz sub/
+		COPY --from=first bar bay
+		`
+	ctx, err := fakeContext(dockerfile, map[string]string{
+		"foo": "abc",
+	})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, err = buildImageFromContext("build1", ctx, true)
+	c.Assert(err, checker.IsNil)
+
+	out, _ := dockerCmd(c, "run", "build1", "cat", "sub/baz", "bay")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "abcabc")
+
+	// the image of the last stage is the result of the build
+	_, _, err = dockerCmdWithError("run", "build1", "cat", "bar")
+	c.Assert(err, checker.NotNil)
+}
+
+func (s *DockerSuite) TestBuildCopyFromCurrentStage(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerfile := `
+		FROM busybox
+		COPY --from=0 foo bar
+		`
+	ctx, err := fakeContext(dockerfile, map[string]string{
+		"foo": "abc",
+	})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, out, err := buildImageFromContextWithOut("build1", ctx, false)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "invalid from flag value 0 refers current build block")
+}
+
+func (s *DockerSuite) TestBuildIntermediateTarget(c *check.C) {
+	dockerfile := `
+		FROM busybox AS build-env
+		CMD ["/dev"]
+		FROM busybox
+		CMD ["/dist"]
+		`
+	ctx, err := fakeContext(dockerfile, map[string]string{})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, err = buildImageFromContext("build1", ctx, true, "--target", "build-env")
+	c.Assert(err, checker.IsNil)
+
+	res := inspectFieldJSON(c, "build1", "Config.Cmd")
+	c.Assert(res, checker.Equals, `["/dev"]`)
+
+	_, out, err := buildImageFromContextWithOut("build1", ctx, true, "--target", "nosuchtarget")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "failed to reach build target")
+}
+
+func (s *DockerSuite) TestBuildArgUsedInFrom(c *check.C) {
+	dockerfile := `
+		ARG tag=latest
+		ARG bar=baz
+		FROM busybox:$tag
+		ARG bar
+		RUN echo $bar > /out
+		CMD ["/out"]
+		`
+	ctx, err := fakeContext(dockerfile, map[string]string{})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, err = buildImageFromContext("build1", ctx, true, "--build-arg", "bar=abc")
+	c.Assert(err, checker.IsNil)
+
+	out, _ := dockerCmd(c, "run", "--rm", "build1", "cat", "/out")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "abc")
+
+	_, out, err = buildImageFromContextWithOut("build2", ctx, true, "--build-arg", "tag=nosuchtag")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "busybox:nosuchtag")
+}
+
+func (s *DockerSuite) TestBuildIntermediateTargetWithArgUsedInFrom(c *check.C) {
+	dockerfile := `
+		ARG base=busybox
+		FROM ${base} AS first
+		CMD ["/first"]
+		FROM ${base} AS second
+		CMD ["/second"]
+		`
+	ctx, err := fakeContext(dockerfile, map[string]string{})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, err = buildImageFromContext("build1", ctx, true, "--target", "first")
+	c.Assert(err, checker.IsNil)
+
+	res := inspectFieldJSON(c, "build1", "Config.Cmd")
+	c.Assert(res, checker.Equals, `["/first"]`)
+}
+
+func (s *DockerSuite) TestBuildArgUsedInStageName(c *check.C) {
+	dockerfile := `
+		ARG name=first
+		FROM busybox AS ${name}
+		`
+	ctx, err := fakeContext(dockerfile, map[string]string{})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, out, err := buildImageFromContextWithOut("build1", ctx, true)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "variables are not supported in the name of a build stage")
+}
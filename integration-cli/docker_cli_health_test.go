@@ -167,3 +167,88 @@ func (s *DockerSuite) TestHealth(c *check.C) {
 	c.Check(out, checker.Equals, "[CMD cat /my status]\n")
 
 }
+
+func (s *DockerSuite) TestHealthHTTPAndTCP(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	imageName := "testhealthhttp"
+	_, err := buildImage(imageName,
+		`FROM busybox
+		RUN mkdir /www && echo OK > /www/status
+		CMD ["httpd", "-f", "-p", "8080", "-h", "/www"]
+		STOPSIGNAL SIGKILL
+		HEALTHCHECK --interval=1s --timeout=30s \
+		  HTTP http://localhost:8080/status 200-299`,
+		true)
+	c.Check(err, check.IsNil)
+
+	out, _ := dockerCmd(c, "inspect", "--format={{.Config.Healthcheck.Test}}", imageName)
+	c.Check(out, checker.Equals, "[HTTP http://localhost:8080/status 200-299]\n")
+
+	name := "test_health_http"
+	dockerCmd(c, "run", "-d", "--name", name, imageName)
+	waitForHealthStatus(c, name, "starting", "healthy")
+	health := getHealth(c, name)
+	last := health.Log[len(health.Log)-1]
+	c.Check(last.ExitCode, checker.Equals, 0)
+	c.Check(last.Output, checker.Contains, "200 OK")
+
+	// Make it fail with a 404
+	dockerCmd(c, "exec", name, "rm", "/www/status")
+	waitForHealthStatus(c, name, "healthy", "unhealthy")
+	dockerCmd(c, "rm", "-f", name)
+
+	// Check the port with a TCP healthcheck from the CLI
+	name = "test_health_tcp"
+	dockerCmd(c, "run", "-d", "--name", name,
+		"--health-interval=1s",
+		"--health-tcp=8080",
+		imageName)
+	waitForHealthStatus(c, name, "starting", "healthy")
+
+	out, _ = dockerCmd(c, "inspect", "--format={{.Config.Healthcheck.Test}}", name)
+	c.Check(out, checker.Equals, "[TCP 8080]\n")
+	dockerCmd(c, "rm", "-f", name)
+
+	// Invalid addresses are rejected when the container is created
+	out, _, err = dockerCmdWithError("create", "--health-tcp=foo", imageName)
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, `invalid TCP healthcheck address "foo"`)
+}
+
+func (s *DockerSuite) TestHealthStartPeriodAndOnUnhealthy(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	// Failures during the start period don't count
+	name := "test_health_start_period"
+	dockerCmd(c, "run", "-d", "--name", name,
+		"--health-cmd=cat /status",
+		"--health-interval=1s",
+		"--health-retries=1",
+		"--health-start-period=1h",
+		"busybox", "top")
+	out, _ := dockerCmd(c, "inspect", "--format={{.Config.Healthcheck.StartPeriod}}", name)
+	c.Check(out, checker.Equals, "1h0m0s\n")
+	time.Sleep(3 * time.Second)
+	health := getHealth(c, name)
+	c.Check(health.Status, checker.Equals, "starting")
+	c.Check(health.FailingStreak, checker.Equals, 0)
+	dockerCmd(c, "rm", "-f", name)
+
+	// The container is stopped once it becomes unhealthy
+	name = "test_health_on_unhealthy"
+	dockerCmd(c, "run", "-d", "--name", name,
+		"--health-cmd=cat /status",
+		"--health-interval=1s",
+		"--health-retries=1",
+		"--on-unhealthy=stop",
+		"--stop-timeout=1",
+		"busybox", "top")
+	err := waitInspect(name, "{{.State.Running}}", "false", 30*time.Second)
+	c.Assert(err, checker.IsNil)
+	dockerCmd(c, "rm", "-f", name)
+
+	out, _, err = dockerCmdWithError("create", "--on-unhealthy=explode", "busybox")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "invalid on-unhealthy action 'explode'")
+}
@@ -199,6 +199,52 @@ func (s *DockerSuite) TestLogsSince(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestLogsUntil(c *check.C) {
+	name := "testlogsuntil"
+	dockerCmd(c, "run", "--name", name, "busybox", "/bin/sh", "-c", "for i in $(seq 1 3); do echo log$i; sleep 1; done")
+	out, _ := dockerCmd(c, "logs", "-t", name)
+
+	log2Line := strings.Split(strings.Split(out, "\n")[1], " ")
+	t, err := time.Parse(time.RFC3339Nano, log2Line[0]) // the timestamp log2 is written
+	c.Assert(err, checker.IsNil)
+	until := t.Format(time.RFC3339Nano)
+
+	// Get logs until the timestamp of log2
+	out, _ = dockerCmd(c, "logs", "--until", until, name)
+
+	// Ensure log2 is the last log message, and that log3 doesn't show up
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(lines, checker.HasLen, 2)
+	c.Assert(lines[0], checker.Equals, "log1")
+	c.Assert(lines[1], checker.Equals, "log2")
+	c.Assert(out, checker.Not(checker.Contains), "log3")
+}
+
+func (s *DockerSuite) TestLogsUntilFollowEnds(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testlogsuntilfollow"
+	dockerCmd(c, "run", "-d", "--name", name, "busybox", "top")
+
+	// Following with an until bound in the past must return instead of
+	// waiting for more output.
+	until := time.Now().Add(-time.Minute).Format(time.RFC3339Nano)
+	cmd := exec.Command(dockerBinary, "logs", "-f", "--until", until, name)
+	c.Assert(cmd.Start(), checker.IsNil)
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-errChan:
+		c.Assert(err, checker.IsNil)
+	case <-time.After(30 * time.Second):
+		cmd.Process.Kill()
+		c.Fatal("docker logs -f --until did not exit")
+	}
+}
+
 func (s *DockerSuite) TestLogsSinceFutureFollow(c *check.C) {
 	// TODO Windows TP5 - Figure out why this test is so flakey. Disabled for now.
 	testRequires(c, DaemonIsLinux)
@@ -326,3 +372,17 @@ func (s *DockerSuite) TestLogsWithDetails(c *check.C) {
 	c.Assert(details[0], checker.Equals, "baz=qux")
 	c.Assert(details[1], checker.Equals, "foo=bar")
 }
+
+func (s *DockerSuite) TestLogsWithLocalCache(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testlogswithlocalcache"
+	// gelf over UDP doesn't need anything to listen on the other end
+	dockerCmd(c, "run", "--name", name, "--log-driver=gelf", "--log-opt", "gelf-address=udp://127.0.0.1:12201", "--log-opt", "cache-enabled=true", "busybox", "echo", "hello from the cache")
+
+	out, _ := dockerCmd(c, "logs", name)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "hello from the cache")
+
+	out, _, err := dockerCmdWithError("run", "--log-opt", "cache-enabled=maybe", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "invalid value for log option cache-enabled")
+}
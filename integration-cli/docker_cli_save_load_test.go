@@ -381,3 +381,114 @@ func (s *DockerSuite) TestSaveLoadNoTag(c *check.C) {
 	c.Assert(out, checker.Contains, "Loaded image: "+name+":latest")
 	c.Assert(out, checker.Not(checker.Contains), "Loaded image ID:")
 }
+
+func (s *DockerSuite) TestSaveLoadOCI(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	name := "saveloadoci"
+
+	_, err := buildImage(name, "FROM busybox\nENV foo=bar", true)
+	c.Assert(err, checker.IsNil, check.Commentf("%v", err))
+
+	id := inspectField(c, name, "Id")
+
+	tmpDir, err := ioutil.TempDir("", "save-load-oci")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+
+	out, _, err := runCommandPipelineWithOutput(
+		exec.Command(dockerBinary, "save", "--format", "oci", name),
+		exec.Command("tar", "-x", "-C", tmpDir),
+	)
+	c.Assert(err, checker.IsNil, check.Commentf("failed to save repo: %s, %v", out, err))
+
+	layout, err := ioutil.ReadFile(filepath.Join(tmpDir, "oci-layout"))
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(layout), checker.Contains, `"imageLayoutVersion":"1.0.0"`)
+
+	var index struct {
+		Manifests []struct {
+			Digest      digest.Digest
+			Annotations map[string]string
+		}
+	}
+	f, err := os.Open(filepath.Join(tmpDir, "index.json"))
+	c.Assert(err, checker.IsNil)
+	defer f.Close()
+	c.Assert(json.NewDecoder(f).Decode(&index), checker.IsNil)
+	c.Assert(index.Manifests, checker.HasLen, 1)
+	c.Assert(index.Manifests[0].Annotations["org.opencontainers.image.ref.name"], checker.Equals, name+":latest")
+
+	// the image config is stored as is, so it is found by the image ID
+	_, err = os.Stat(filepath.Join(tmpDir, "blobs", "sha256", strings.TrimPrefix(id, "sha256:")))
+	c.Assert(err, checker.IsNil)
+
+	deleteImages(name)
+
+	out, _, err = runCommandPipelineWithOutput(
+		exec.Command("tar", "-c", "-C", tmpDir, "."),
+		exec.Command(dockerBinary, "load"))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to load repo: %s, %v", out, err))
+	c.Assert(out, checker.Contains, "Loaded image: "+name+":latest")
+	c.Assert(inspectField(c, name, "Id"), checker.Equals, id)
+}
+
+func (s *DockerSuite) TestSaveInvalidFormat(c *check.C) {
+	out, _, err := dockerCmdWithError("save", "--format", "foo", "busybox")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, `invalid format "foo"`)
+}
+
+func (s *DockerSuite) TestSaveLoadExcludeLayersFrom(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	name := "saveloadexcludelayers"
+
+	_, err := buildImage(name, "FROM busybox\nRUN echo foo > /foo", true)
+	c.Assert(err, checker.IsNil, check.Commentf("%v", err))
+
+	id := inspectField(c, name, "Id")
+
+	tmpDir, err := ioutil.TempDir("", "save-load-exclude-layers")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+
+	out, _, err := runCommandPipelineWithOutput(
+		exec.Command(dockerBinary, "save", "--exclude-layers-from", "busybox", name),
+		exec.Command("tar", "-x", "-C", tmpDir),
+	)
+	c.Assert(err, checker.IsNil, check.Commentf("failed to save repo: %s, %v", out, err))
+
+	var manifest []struct {
+		Layers []string
+	}
+	f, err := os.Open(filepath.Join(tmpDir, "manifest.json"))
+	c.Assert(err, checker.IsNil)
+	defer f.Close()
+	c.Assert(json.NewDecoder(f).Decode(&manifest), checker.IsNil)
+	c.Assert(manifest, checker.HasLen, 1)
+
+	// only the layer added on top of busybox is in the archive
+	var saved int
+	for _, l := range manifest[0].Layers {
+		if _, err := os.Lstat(filepath.Join(tmpDir, l)); err == nil {
+			saved++
+		}
+	}
+	c.Assert(saved, checker.Equals, 1)
+
+	deleteImages(name)
+
+	out, _, err = runCommandPipelineWithOutput(
+		exec.Command("tar", "-c", "-C", tmpDir, "."),
+		exec.Command(dockerBinary, "load"))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to load repo: %s, %v", out, err))
+	c.Assert(out, checker.Contains, "Loaded image: "+name+":latest")
+	c.Assert(inspectField(c, name, "Id"), checker.Equals, id)
+}
+
+func (s *DockerSuite) TestSaveExcludeLayersFromOCI(c *check.C) {
+	out, _, err := dockerCmdWithError("save", "--format", "oci", "--exclude-layers-from", "busybox", "busybox")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "layers cannot be excluded")
+}
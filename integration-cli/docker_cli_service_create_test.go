@@ -62,6 +62,21 @@ func (s *DockerSwarmSuite) TestServiceCreateMountVolume(c *check.C) {
 	c.Assert(mounts[0].RW, checker.Equals, true)
 }
 
+func (s *DockerSwarmSuite) TestServiceCreateMountVolumeTemplate(c *check.C) {
+	d := s.AddDaemon(c, true, true)
+	out, err := d.Cmd("service", "create", "--replicas", "2", "--mount", "type=volume,source=data-{{.Task.Slot}},target=/data", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	waitAndAssert(c, defaultReconciliationTimeout, d.CheckActiveContainerCount, checker.Equals, 2)
+
+	// every task gets the volume of its slot
+	out, err = d.Cmd("volume", "ls", "-q")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	volumes := strings.Fields(out)
+	c.Assert(volumes, checker.HasLen, 2)
+	c.Assert(volumes, checker.DeepEquals, []string{"data-1", "data-2"})
+}
+
 func (s *DockerSwarmSuite) TestServiceCreateWithSecretSimple(c *check.C) {
 	d := s.AddDaemon(c, true, true)
 
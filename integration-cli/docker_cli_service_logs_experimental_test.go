@@ -8,6 +8,7 @@ import (
 	"io"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/pkg/integration/checker"
 	"github.com/go-check/check"
@@ -94,3 +95,46 @@ func (s *DockerSwarmSuite) TestServiceLogsFollow(c *check.C) {
 
 	c.Assert(cmd.Process.Kill(), checker.IsNil)
 }
+
+func (s *DockerSwarmSuite) TestServiceLogsTaskTarget(c *check.C) {
+	testRequires(c, ExperimentalDaemon)
+
+	d := s.AddDaemon(c, true, true)
+
+	name := "TestServiceLogsTaskTarget"
+	out, err := d.Cmd("service", "create", "--name", name, "--replicas", "2", "busybox",
+		"sh", "-c", "echo log test; tail -f /dev/null")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	// make sure task has been deployed.
+	waitAndAssert(c, defaultReconciliationTimeout, d.CheckActiveContainerCount, checker.Equals, 2)
+
+	tasks := d.GetServiceTasks(c, strings.TrimSpace(out))
+	c.Assert(tasks, checker.HasLen, 2)
+	task := tasks[0]
+
+	// only the logs of the task are returned
+	out, err = d.Cmd("service", "logs", task.ID)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	c.Assert(lines, checker.HasLen, 1)
+	c.Assert(lines[0], checker.Contains, fmt.Sprintf("%s.%d.%s@", name, task.Slot, task.ID[:12]))
+	c.Assert(lines[0], checker.HasSuffix, "log test")
+
+	out, err = d.Cmd("service", "logs", "--no-task-ids", task.ID)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.HasPrefix, fmt.Sprintf("%s.%d@", name, task.Slot))
+
+	out, err = d.Cmd("service", "logs", "--raw", task.ID)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "log test")
+
+	// nothing was logged before the task was created
+	out, err = d.Cmd("service", "logs", "--until", task.Meta.CreatedAt.Add(-time.Second).Format(time.RFC3339Nano), name)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(strings.TrimSpace(out), checker.Equals, "")
+
+	out, err = d.Cmd("service", "logs", "notexist")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "No such service or task: notexist")
+}
@@ -128,3 +128,40 @@ func (s *DockerSwarmSuite) TestServiceUpdateSecrets(c *check.C) {
 	c.Assert(json.Unmarshal([]byte(out), &refs), checker.IsNil)
 	c.Assert(refs, checker.HasLen, 0)
 }
+
+func (s *DockerSwarmSuite) TestServiceRollback(c *check.C) {
+	d := s.AddDaemon(c, true, true)
+	out, err := d.Cmd("service", "create", "--name=test", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	// there is nothing to roll back to yet
+	out, err = d.Cmd("service", "rollback", "test")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "service does not have a previous specification to roll back to")
+
+	out, err = d.Cmd("service", "update", "--label-add", "foo=bar", "test")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	service := d.GetService(c, "test")
+	c.Assert(service.Spec.Labels, checker.HasLen, 1)
+
+	out, err = d.Cmd("service", "rollback", "test")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	service = d.GetService(c, "test")
+	c.Assert(service.Spec.Labels, checker.HasLen, 0)
+	c.Assert(service.PreviousSpec.Labels["foo"], checker.Equals, "bar")
+}
+
+func (s *DockerSwarmSuite) TestServiceRollbackWithRegistryAuth(c *check.C) {
+	d := s.AddDaemon(c, true, true)
+	out, err := d.Cmd("service", "create", "--name=test", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	out, err = d.Cmd("service", "update", "--label-add", "foo=bar", "test")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	// the rollback goes through the same code as "service update --rollback"
+	out, err = d.Cmd("service", "rollback", "--with-registry-auth", "test")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	service := d.GetService(c, "test")
+	c.Assert(service.Spec.Labels, checker.HasLen, 0)
+}
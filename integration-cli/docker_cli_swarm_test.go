@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -1427,3 +1428,31 @@ Options:`
 	c.Assert(err, checker.IsNil, check.Commentf("out: %v", out))
 	c.Assert(out, checker.Contains, expectedOutput, check.Commentf(out))
 }
+
+func (s *DockerSwarmSuite) TestSwarmClusterEvents(c *check.C) {
+	d := s.AddDaemon(c, true, true)
+
+	since := strconv.FormatInt(time.Now().Unix(), 10)
+
+	out, err := d.Cmd("service", "create", "--name", "test", "busybox", "top")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	serviceID := strings.TrimSpace(out)
+
+	// the changes are picked up by polling the swarm objects
+	getEvents := func(c *check.C) (interface{}, check.CommentInterface) {
+		until := strconv.FormatInt(time.Now().Unix(), 10)
+		out, err := d.Cmd("events", "--since", since, "--until", until, "--filter", "type=service")
+		c.Assert(err, checker.IsNil, check.Commentf(out))
+		return out, nil
+	}
+	waitAndAssert(c, defaultReconciliationTimeout, getEvents, checker.Contains, "task_state: running")
+
+	result, _ := getEvents(c)
+	events := result.(string)
+	c.Assert(events, checker.Contains, fmt.Sprintf("service create %s (name=test)", serviceID))
+	c.Assert(events, checker.Not(checker.Contains), "container")
+
+	out, err = d.Cmd("service", "rm", "test")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	waitAndAssert(c, defaultReconciliationTimeout, getEvents, checker.Contains, fmt.Sprintf("service remove %s (name=test)", serviceID))
+}
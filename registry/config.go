@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/Sirupsen/logrus"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/reference"
@@ -18,6 +19,10 @@ type ServiceOptions struct {
 	Mirrors            []string `json:"registry-mirrors,omitempty"`
 	InsecureRegistries []string `json:"insecure-registries,omitempty"`
 
+	// RegistryMirrors holds the mirrors of the registries other than the
+	// official one, by registry name (`host` or `host:port`).
+	RegistryMirrors map[string][]string `json:"registry-mirrors-for,omitempty"`
+
 	// V2Only controls access to legacy registries.  If it is set to true via the
 	// command line flag the daemon will not attempt to contact v1 legacy registries
 	V2Only bool `json:"disable-legacy-registry,omitempty"`
@@ -27,6 +32,10 @@ type ServiceOptions struct {
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+
+	// RegistryMirrors holds the mirrors of the registries other than the
+	// official one, in order of preference.
+	RegistryMirrors map[string][]string
 }
 
 var (
@@ -71,6 +80,7 @@ func (options *ServiceOptions) InstallCliFlags(flags *pflag.FlagSet) {
 	insecureRegistries := opts.NewNamedListOptsRef("insecure-registries", &options.InsecureRegistries, ValidateIndexName)
 
 	flags.Var(mirrors, "registry-mirror", "Preferred Docker registry mirror")
+	flags.Var(newRegistryMirrorsOpt(&options.RegistryMirrors), "registry-mirror-for", "Preferred mirror of a registry other than Docker Hub (registry=mirror)")
 	flags.Var(insecureRegistries, "insecure-registry", "Enable insecure registry communication")
 
 	options.installCliPlatformFlags(flags)
@@ -90,10 +100,24 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 	}
 
 	config.LoadInsecureRegistries(options.InsecureRegistries)
+	if err := config.LoadRegistryMirrors(options.RegistryMirrors); err != nil {
+		logrus.Warnf("Ignoring registry mirrors: %v", err)
+	}
 
 	return config
 }
 
+// LoadRegistryMirrors loads the mirrors of the registries other than the
+// official one to config
+func (config *serviceConfig) LoadRegistryMirrors(mirrors map[string][]string) error {
+	registryMirrors, err := ValidateRegistryMirrors(mirrors)
+	if err != nil {
+		return err
+	}
+	config.RegistryMirrors = registryMirrors
+	return nil
+}
+
 // LoadInsecureRegistries loads insecure registries to config
 func (config *serviceConfig) LoadInsecureRegistries(registries []string) error {
 	// Localhost is by default considered as an insecure registry
@@ -222,6 +246,96 @@ func ValidateMirror(val string) (string, error) {
 	return fmt.Sprintf("%s://%s/", uri.Scheme, uri.Host), nil
 }
 
+// ValidateRegistryMirrors validates the mirrors of registries other than the
+// official one, and returns them with normalized registry names and mirror
+// URLs.
+func ValidateRegistryMirrors(mirrors map[string][]string) (map[string][]string, error) {
+	validated := make(map[string][]string, len(mirrors))
+	for registry, registryMirrors := range mirrors {
+		name, err := validateMirroredRegistry(registry)
+		if err != nil {
+			return nil, err
+		}
+		for _, mirror := range registryMirrors {
+			mirror, err := ValidateMirror(mirror)
+			if err != nil {
+				return nil, err
+			}
+			validated[name] = append(validated[name], mirror)
+		}
+	}
+	return validated, nil
+}
+
+// validateMirroredRegistry validates the name of a registry that mirrors
+// are configured for.
+func validateMirroredRegistry(val string) (string, error) {
+	name, err := ValidateIndexName(val)
+	if err != nil {
+		return "", err
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("Invalid registry name (%s). Must be host or host:port.", val)
+	}
+	if name == IndexName {
+		return "", fmt.Errorf("Mirrors of %s must be configured with registry-mirrors", IndexName)
+	}
+	return name, nil
+}
+
+// registryMirrorsOpt is the value of the registry-mirror-for flag, which
+// adds a mirror to the mirrors of a registry, using the syntax
+// registry=mirror.
+type registryMirrorsOpt struct {
+	values *map[string][]string
+}
+
+func newRegistryMirrorsOpt(values *map[string][]string) *registryMirrorsOpt {
+	return &registryMirrorsOpt{values: values}
+}
+
+// Set adds the mirror of a registry in the registry=mirror format
+func (o *registryMirrorsOpt) Set(val string) error {
+	parts := strings.SplitN(val, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid registry mirror %s: must be registry=mirror", val)
+	}
+	registry, err := validateMirroredRegistry(parts[0])
+	if err != nil {
+		return err
+	}
+	mirror, err := ValidateMirror(parts[1])
+	if err != nil {
+		return err
+	}
+	if *o.values == nil {
+		*o.values = make(map[string][]string)
+	}
+	(*o.values)[registry] = append((*o.values)[registry], mirror)
+	return nil
+}
+
+// String returns the mirrors in the registry=mirror format
+func (o *registryMirrorsOpt) String() string {
+	var values []string
+	for registry, mirrors := range *o.values {
+		for _, mirror := range mirrors {
+			values = append(values, registry+"="+mirror)
+		}
+	}
+	return fmt.Sprintf("%v", values)
+}
+
+// Type returns the type of the flag
+func (o *registryMirrorsOpt) Type() string {
+	return "registry-mirror"
+}
+
+// Name returns the name of the option in the configuration file
+func (o *registryMirrorsOpt) Name() string {
+	return "registry-mirrors-for"
+}
+
 // ValidateIndexName validates an index name.
 func ValidateIndexName(val string) (string, error) {
 	if val == reference.LegacyDefaultHostname {
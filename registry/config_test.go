@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -47,3 +48,63 @@ func TestValidateMirror(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRegistryMirrors(t *testing.T) {
+	mirrors, err := ValidateRegistryMirrors(map[string][]string{
+		"registry.example.com":   {"https://mirror-1.com", "http://mirror-2.com:5000"},
+		"localhost:5000":         {"http://localhost:5001"},
+		"registry.example.com:1": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"registry.example.com": {"https://mirror-1.com/", "http://mirror-2.com:5000/"},
+		"localhost:5000":       {"http://localhost:5001/"},
+	}
+	if !reflect.DeepEqual(mirrors, expected) {
+		t.Fatalf("Expected %v, got %v", expected, mirrors)
+	}
+
+	invalid := []map[string][]string{
+		{"docker.io": {"https://mirror-1.com"}},
+		{"index.docker.io": {"https://mirror-1.com"}},
+		{"https://registry.example.com": {"https://mirror-1.com"}},
+		{"registry.example.com/foo": {"https://mirror-1.com"}},
+		{"-registry.example.com": {"https://mirror-1.com"}},
+		{"registry.example.com": {"ftp://mirror-1.com"}},
+		{"registry.example.com": {"https://mirror-1.com/v2/"}},
+	}
+	for _, m := range invalid {
+		if _, err := ValidateRegistryMirrors(m); err == nil {
+			t.Errorf("ValidateRegistryMirrors(%v) should fail", m)
+		}
+	}
+}
+
+func TestRegistryMirrorsOpt(t *testing.T) {
+	var mirrors map[string][]string
+	opt := newRegistryMirrorsOpt(&mirrors)
+	for _, val := range []string{
+		"registry.example.com=https://mirror-1.com",
+		"registry.example.com=https://mirror-2.com",
+		"localhost:5000=http://localhost:5001",
+	} {
+		if err := opt.Set(val); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := map[string][]string{
+		"registry.example.com": {"https://mirror-1.com/", "https://mirror-2.com/"},
+		"localhost:5000":       {"http://localhost:5001/"},
+	}
+	if !reflect.DeepEqual(mirrors, expected) {
+		t.Fatalf("Expected %v, got %v", expected, mirrors)
+	}
+
+	for _, val := range []string{"https://mirror-1.com", "docker.io=https://mirror-1.com", "registry.example.com=mirror"} {
+		if err := opt.Set(val); err == nil {
+			t.Errorf("Set(%s) should fail", val)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -686,6 +687,53 @@ func TestMirrorEndpointLookup(t *testing.T) {
 	}
 }
 
+func TestRegistryMirrorEndpointLookup(t *testing.T) {
+	s := DefaultService{config: newServiceConfig(ServiceOptions{
+		RegistryMirrors: map[string][]string{
+			"registry.example.com": {"https://mirror1.example.com", "http://mirror2.example.com"},
+		},
+	})}
+
+	pullAPIEndpoints, err := s.LookupPullEndpoints("registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hosts []string
+	for _, pe := range pullAPIEndpoints {
+		if pe.Version == APIVersion2 {
+			hosts = append(hosts, pe.URL.Host)
+		}
+	}
+	expected := []string{"mirror1.example.com", "mirror2.example.com", "registry.example.com"}
+	if !reflect.DeepEqual(hosts, expected) {
+		t.Fatalf("Expected pull endpoints %v, got %v", expected, hosts)
+	}
+	if !pullAPIEndpoints[0].Mirror || !pullAPIEndpoints[1].Mirror || pullAPIEndpoints[2].Mirror {
+		t.Fatal("Only the endpoints of the mirrors should be marked as mirrors")
+	}
+
+	pushAPIEndpoints, err := s.LookupPushEndpoints("registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pe := range pushAPIEndpoints {
+		if pe.Mirror {
+			t.Fatal("Push endpoint should not contain mirror")
+		}
+	}
+
+	// other registries don't use the mirrors
+	pullAPIEndpoints, err = s.LookupPullEndpoints("other.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pe := range pullAPIEndpoints {
+		if pe.Mirror {
+			t.Fatal("Pull endpoint of other registry should not contain mirror")
+		}
+	}
+}
+
 func TestPushRegistryTag(t *testing.T) {
 	r := spawnTestRegistrySession(t)
 	repoRef, err := reference.ParseNamed(REPO)
@@ -32,6 +32,7 @@ type Service interface {
 	ServiceConfig() *registrytypes.ServiceConfig
 	TLSConfig(hostname string) (*tls.Config, error)
 	LoadInsecureRegistries([]string) error
+	LoadRegistryMirrors(map[string][]string) error
 }
 
 // DefaultService is a registry service. It tracks configuration data such as a list
@@ -81,6 +82,15 @@ func (s *DefaultService) LoadInsecureRegistries(registries []string) error {
 	return s.config.LoadInsecureRegistries(registries)
 }
 
+// LoadRegistryMirrors loads the mirrors of the registries other than the
+// official one for Service
+func (s *DefaultService) LoadRegistryMirrors(mirrors map[string][]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config.LoadRegistryMirrors(mirrors)
+}
+
 // Auth contacts the public registry with the provided credentials,
 // and returns OK if authentication was successful.
 // It can be used to verify the validity of a client's credentials.
@@ -258,7 +268,8 @@ func (s *DefaultService) tlsConfigForMirror(mirrorURL *url.URL) (*tls.Config, er
 
 // LookupPullEndpoints creates a list of endpoints to try to pull from, in order of preference.
 // It gives preference to v2 endpoints over v1, mirrors over the actual
-// registry, and HTTPS over plain HTTP.
+// registry, and HTTPS over plain HTTP. The mirrors are tried in the order
+// they are configured in.
 func (s *DefaultService) LookupPullEndpoints(hostname string) (endpoints []APIEndpoint, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
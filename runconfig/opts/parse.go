@@ -102,8 +102,13 @@ type ContainerOptions struct {
 	shmSize            string
 	noHealthcheck      bool
 	healthCmd          string
+	healthHTTP         string
+	healthHTTPStatus   string
+	healthTCP          string
 	healthInterval     time.Duration
 	healthTimeout      time.Duration
+	healthStartPeriod  time.Duration
+	onUnhealthy        string
 	healthRetries      int
 	runtime            string
 	autoRemove         bool
@@ -219,10 +224,20 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 
 	// Health-checking
 	flags.StringVar(&copts.healthCmd, "health-cmd", "", "Command to run to check health")
+	flags.StringVar(&copts.healthHTTP, "health-http", "", "URL to get from the container to check health")
+	flags.SetAnnotation("health-http", "version", []string{"1.26"})
+	flags.StringVar(&copts.healthHTTPStatus, "health-http-status", "", "Range of HTTP status codes to consider healthy (default 200-399)")
+	flags.SetAnnotation("health-http-status", "version", []string{"1.26"})
+	flags.StringVar(&copts.healthTCP, "health-tcp", "", "Address ([host:]port) to connect to from the container to check health")
+	flags.SetAnnotation("health-tcp", "version", []string{"1.26"})
 	flags.DurationVar(&copts.healthInterval, "health-interval", 0, "Time between running the check (ns|us|ms|s|m|h) (default 0s)")
 	flags.IntVar(&copts.healthRetries, "health-retries", 0, "Consecutive failures needed to report unhealthy")
 	flags.DurationVar(&copts.healthTimeout, "health-timeout", 0, "Maximum time to allow one check to run (ns|us|ms|s|m|h) (default 0s)")
+	flags.DurationVar(&copts.healthStartPeriod, "health-start-period", 0, "Start period for the container to initialize before failed checks count (ns|us|ms|s|m|h) (default 0s)")
+	flags.SetAnnotation("health-start-period", "version", []string{"1.26"})
 	flags.BoolVar(&copts.noHealthcheck, "no-healthcheck", false, "Disable any container-specified HEALTHCHECK")
+	flags.StringVar(&copts.onUnhealthy, "on-unhealthy", "", "Action to take when the container becomes unhealthy (none, restart, stop)")
+	flags.SetAnnotation("on-unhealthy", "version", []string{"1.26"})
 
 	// Resource management
 	flags.Uint16Var(&copts.blkioWeight, "blkio-weight", 0, "Block IO (relative weight), between 10 and 1000, or 0 to disable (default 0)")
@@ -489,8 +504,12 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 	// Healthcheck
 	var healthConfig *container.HealthConfig
 	haveHealthSettings := copts.healthCmd != "" ||
+		copts.healthHTTP != "" ||
+		copts.healthHTTPStatus != "" ||
+		copts.healthTCP != "" ||
 		copts.healthInterval != 0 ||
 		copts.healthTimeout != 0 ||
+		copts.healthStartPeriod != 0 ||
 		copts.healthRetries != 0
 	if copts.noHealthcheck {
 		if haveHealthSettings {
@@ -500,9 +519,29 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		healthConfig = &container.HealthConfig{Test: test}
 	} else if haveHealthSettings {
 		var probe strslice.StrSlice
+		probes := 0
 		if copts.healthCmd != "" {
 			args := []string{"CMD-SHELL", copts.healthCmd}
 			probe = strslice.StrSlice(args)
+			probes++
+		}
+		if copts.healthHTTP != "" {
+			args := []string{"HTTP", copts.healthHTTP}
+			if copts.healthHTTPStatus != "" {
+				args = append(args, copts.healthHTTPStatus)
+			}
+			probe = strslice.StrSlice(args)
+			probes++
+		} else if copts.healthHTTPStatus != "" {
+			return nil, nil, nil, fmt.Errorf("--health-http-status requires --health-http")
+		}
+		if copts.healthTCP != "" {
+			args := []string{"TCP", copts.healthTCP}
+			probe = strslice.StrSlice(args)
+			probes++
+		}
+		if probes > 1 {
+			return nil, nil, nil, fmt.Errorf("--health-cmd, --health-http and --health-tcp are mutually exclusive")
 		}
 		if copts.healthInterval < 0 {
 			return nil, nil, nil, fmt.Errorf("--health-interval cannot be negative")
@@ -510,12 +549,16 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		if copts.healthTimeout < 0 {
 			return nil, nil, nil, fmt.Errorf("--health-timeout cannot be negative")
 		}
+		if copts.healthStartPeriod < 0 {
+			return nil, nil, nil, fmt.Errorf("--health-start-period cannot be negative")
+		}
 
 		healthConfig = &container.HealthConfig{
-			Test:     probe,
-			Interval: copts.healthInterval,
-			Timeout:  copts.healthTimeout,
-			Retries:  copts.healthRetries,
+			Test:        probe,
+			Interval:    copts.healthInterval,
+			Timeout:     copts.healthTimeout,
+			StartPeriod: copts.healthStartPeriod,
+			Retries:     copts.healthRetries,
 		}
 	}
 
@@ -608,6 +651,7 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		CapDrop:        strslice.StrSlice(copts.capDrop.GetAll()),
 		GroupAdd:       copts.groupAdd.GetAll(),
 		RestartPolicy:  restartPolicy,
+		OnUnhealthy:    copts.onUnhealthy,
 		SecurityOpt:    securityOpts,
 		StorageOpt:     storageOpts,
 		ReadonlyRootfs: copts.readonlyRootfs,
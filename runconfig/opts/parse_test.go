@@ -619,6 +619,36 @@ func TestParseHealth(t *testing.T) {
 	checkError("--no-healthcheck conflicts with --health-* options",
 		"--no-healthcheck", "--health-cmd=/check.sh -q", "img", "cmd")
 
+	health = checkOk("--health-http=http://localhost:8080/health", "--health-http-status=200-299", "img", "cmd")
+	if len(health.Test) != 3 || health.Test[0] != "HTTP" || health.Test[1] != "http://localhost:8080/health" || health.Test[2] != "200-299" {
+		t.Fatalf("--health-http: got %#v", health.Test)
+	}
+
+	health = checkOk("--health-tcp=5432", "img", "cmd")
+	if len(health.Test) != 2 || health.Test[0] != "TCP" || health.Test[1] != "5432" {
+		t.Fatalf("--health-tcp: got %#v", health.Test)
+	}
+
+	checkError("--health-cmd, --health-http and --health-tcp are mutually exclusive",
+		"--health-cmd=/check.sh -q", "--health-tcp=5432", "img", "cmd")
+	checkError("--health-http-status requires --health-http",
+		"--health-http-status=200", "img", "cmd")
+
+	health = checkOk("--health-start-period=1m", "img", "cmd")
+	if health.StartPeriod != time.Minute {
+		t.Fatalf("--health-start-period: got %#v", health)
+	}
+	checkError("--health-start-period cannot be negative",
+		"--health-start-period=-1s", "img", "cmd")
+
+	_, hostconfig, _, err := parseRun([]string{"--on-unhealthy=restart", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostconfig.OnUnhealthy != "restart" {
+		t.Fatalf("--on-unhealthy: got %q", hostconfig.OnUnhealthy)
+	}
+
 	health = checkOk("--health-timeout=2s", "--health-retries=3", "--health-interval=4.5s", "img", "cmd")
 	if health.Timeout != 2*time.Second || health.Retries != 3 || health.Interval != 4500*time.Millisecond {
 		t.Fatalf("--health-*: got %#v", health)